@@ -0,0 +1,34 @@
+package responder
+
+import "io"
+
+// defaultBufferLimit the bytes buffered before a response is streamed
+const defaultBufferLimit = 32 << 10
+
+// WithBufferLimit sets how many bytes of the body are buffered before the
+// response is committed and the rest of it is streamed. A failure while
+// the body is buffered still produces a clean http.StatusInternalServerError,
+// a negative limit buffers the whole body
+func WithBufferLimit(limit int) Option {
+	return func(config *Config) {
+		config.BufferLimit = limit
+	}
+}
+
+// trimNewline drops the newline json.Encoder writes after every value,
+// each value is written with a single call
+type trimNewline struct {
+	w io.Writer
+}
+
+func (t trimNewline) Write(p []byte) (int, error) {
+	if len(p) == 0 || p[len(p)-1] != '\n' {
+		return t.w.Write(p)
+	}
+
+	if _, err := t.w.Write(p[:len(p)-1]); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,93 @@
+package responder_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestBufferLimit(t *testing.T) {
+
+	t.Run("it streams the same body it would buffer", func(t *testing.T) {
+		payload := benchmarkPayload()
+		expected, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, limit := range []int{-1, 0, 512} {
+			rr := httptest.NewRecorder()
+			responder.New(rr, responder.WithBufferLimit(limit)).OK(payload)
+
+			assertOK(t, rr)
+			assertIsJSON(t, rr)
+			if rr.Body.String() != string(expected) {
+				t.Errorf("handler returned wrong body with limit %d", limit)
+			}
+		}
+	})
+
+	t.Run("it returns http status 500 when encoding fails before streaming", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).OK([]interface{}{"Henry", func() {}})
+
+		assertInternalError(t, rr)
+		if strings.HasPrefix(rr.Body.String(), "[") {
+			t.Errorf("handler returned partial body: got %v", rr.Body.String())
+		}
+	})
+
+	t.Run("it aborts the response when encoding fails while streaming", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+
+		defer func() {
+			if recovered := recover(); recovered != http.ErrAbortHandler {
+				t.Errorf("expected the response to be aborted: got %v", recovered)
+			}
+
+			assertOK(t, rr)
+		}()
+
+		responder.New(rr, responder.WithBufferLimit(8)).OK([]interface{}{strings.Repeat("Henry", 10), func() {}})
+	})
+}
+
+func BenchmarkBufferLimit(b *testing.B) {
+	payload := benchmarkPayload()
+
+	limits := []struct {
+		limit int
+		name  string
+	}{
+		{limit: -1, name: "buffered"},
+		{limit: 32 << 10, name: "streamed"},
+	}
+
+	for _, item := range limits {
+		b.Run(item.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				responder.New(discardWriter{header: make(http.Header)}, responder.WithBufferLimit(item.limit)).OK(payload)
+			}
+		})
+	}
+}
+
+// discardWriter a http.ResponseWriter that doesn't hold the body
+type discardWriter struct {
+	header http.Header
+}
+
+func (w discardWriter) Header() http.Header {
+	return w.header
+}
+
+func (discardWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (discardWriter) WriteHeader(int) {}
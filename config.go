@@ -0,0 +1,86 @@
+package responder
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option changes the configuration a Respond is built with
+type Option func(*Config)
+
+// Config holds the settings used by a Respond to render its responses
+type Config struct {
+	// Envelope wraps the payloads sent by OK when set, a nil
+	// Envelope writes the payloads as they are
+	Envelope *Envelope
+	// ErrorFormat selects how the error responses are rendered
+	ErrorFormat ErrorFormat
+	// Precedence picks the ErrorFormatter rendering an error wrapping several of them
+	Precedence Precedence
+	// ErrorMap maps the errors not implementing ErrorFormatter to their responses
+	ErrorMap *ErrorMap
+	// Translator localizes the message and description of the error responses
+	Translator Translator
+	// Language the language used when none of the accepted ones is translated
+	Language string
+	// BufferLimit the bytes of the body buffered before the response
+	// is streamed, a negative limit buffers the whole body
+	BufferLimit int
+	// FlushInterval how often the streams are flushed to the client,
+	// zero flushes every item right away
+	FlushInterval time.Duration
+	// Heartbeat how often the event streams send a heartbeat comment,
+	// zero disables them
+	Heartbeat time.Duration
+	// Debug renders the details of the unexpected server errors
+	Debug bool
+	// ErrorLogger receives the unexpected server errors hidden from the client
+	ErrorLogger ErrorLogger
+	// Reporter observes every error response
+	Reporter Reporter
+
+	// encoders the media types the responses can be encoded into
+	encoders []mediaEncoder
+
+	// request is the request being responded, it never belongs
+	// to the package-level configuration
+	request *http.Request
+}
+
+// defaultConfig is the configuration every New starts from
+var defaultConfig = Config{
+	BufferLimit:   defaultBufferLimit,
+	FlushInterval: defaultFlushInterval,
+	Heartbeat:     defaultHeartbeat,
+	ErrorLogger:   logError,
+	ErrorMap:      DefaultErrorMap(),
+	Language:      defaultLanguage,
+	encoders:      defaultEncoders(),
+}
+
+// Configure changes the package-level configuration used by New, it is
+// meant to be called once while the application boots
+func Configure(opts ...Option) {
+	for _, opt := range opts {
+		opt(&defaultConfig)
+	}
+
+	defaultConfig.request = nil
+}
+
+func newConfig(opts []Option) Config {
+	config := defaultConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return config
+}
+
+// WithRequest sets the request being responded, the features
+// depending on it (like the problem instance) use it when set
+func WithRequest(r *http.Request) Option {
+	return func(config *Config) {
+		config.request = r
+	}
+}
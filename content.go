@@ -0,0 +1,42 @@
+package responder
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Content serves the content honoring the Range, If-Range and conditional
+// headers of the request, so the clients can resume their downloads.
+// The name is used to detect the content type when it isn't set, and a
+// strong ETag is derived from the size and the nanoseconds of the modtime when there's none.
+// The request given at construction time is used when r is nil
+func (response *HttpResponse) Content(r *http.Request, name string, modtime time.Time, content io.ReadSeeker) {
+	if r == nil {
+		r = response.config.request
+	}
+
+	if r == nil {
+		// without the request there aren't ranges nor conditions to honor
+		r = &http.Request{Method: http.MethodGet, Header: make(http.Header)}
+	}
+
+	header := response.writer.Header()
+	if header.Get("ETag") == "" && !modtime.IsZero() {
+		size, err := content.Seek(0, io.SeekEnd)
+		if err == nil {
+			_, err = content.Seek(0, io.SeekStart)
+		}
+
+		if err != nil {
+			response.InternalServerError(err)
+			return
+		}
+
+		header.Set("ETag", fmt.Sprintf(`"%x-%x"`, modtime.UnixNano(), size))
+	}
+
+	http.ServeContent(response.writer, r, name, modtime, content)
+	response.writer.commit()
+}
@@ -0,0 +1,141 @@
+package responder_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestContent(t *testing.T) {
+	modtime := time.Date(2023, time.February, 17, 0, 0, 0, 0, time.UTC)
+	content := "0123456789"
+
+	serve := func(t *testing.T, headers map[string]string) *httptest.ResponseRecorder {
+		req := buildRequest(t)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithRequest(req)).Content(nil, "report.txt", modtime, strings.NewReader(content))
+		return rr
+	}
+
+	etag := serve(t, nil).Header().Get("ETag")
+
+	cases := []struct {
+		headers    map[string]string
+		expectCode int
+		expectBody string
+		name       string
+	}{
+		{
+			expectCode: http.StatusOK,
+			expectBody: content,
+			name:       "it returns the whole content",
+		},
+		{
+			headers:    map[string]string{"Range": "bytes=2-5"},
+			expectCode: http.StatusPartialContent,
+			expectBody: "2345",
+			name:       "it returns a single range",
+		},
+		{
+			headers:    map[string]string{"Range": "bytes=20-"},
+			expectCode: http.StatusRequestedRangeNotSatisfiable,
+			name:       "it returns http status 416 when the range can not be satisfied",
+		},
+		{
+			headers:    map[string]string{"Range": "bytes=0-1", "If-Range": etag},
+			expectCode: http.StatusPartialContent,
+			expectBody: "01",
+			name:       "it returns the range when If-Range matches",
+		},
+		{
+			headers:    map[string]string{"Range": "bytes=0-1", "If-Range": `"stale"`},
+			expectCode: http.StatusOK,
+			expectBody: content,
+			name:       "it returns the whole content when If-Range doesn't match",
+		},
+		{
+			headers:    map[string]string{"If-None-Match": etag},
+			expectCode: http.StatusNotModified,
+			name:       "it returns http status 304 when the ETag matches",
+		},
+		{
+			headers:    map[string]string{"If-Modified-Since": modtime.Format(http.TimeFormat)},
+			expectCode: http.StatusNotModified,
+			name:       "it returns http status 304 when it wasn't modified",
+		},
+		{
+			headers:    map[string]string{"If-Match": `"stale"`},
+			expectCode: http.StatusPreconditionFailed,
+			name:       "it returns http status 412 when the precondition fails",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			rr := serve(t, item.headers)
+
+			assertStatusCode(t, item.expectCode, rr.Code)
+			if item.expectBody != "" && rr.Body.String() != item.expectBody {
+				t.Errorf("handler returned wrong body: got %v want %v", rr.Body.String(), item.expectBody)
+			}
+		})
+	}
+
+	t.Run("it sets the download headers", func(t *testing.T) {
+		rr := serve(t, nil)
+
+		expected := map[string]string{
+			"Accept-Ranges":  "bytes",
+			"Content-Length": "10",
+			"Content-Type":   "text/plain; charset=utf-8",
+			"Last-Modified":  modtime.Format(http.TimeFormat),
+		}
+
+		for name, value := range expected {
+			if rr.Header().Get(name) != value {
+				t.Errorf("handler returned wrong %s: got %v want %v", name, rr.Header().Get(name), value)
+			}
+		}
+
+		if !strings.HasPrefix(etag, `"`) {
+			t.Errorf("expected a strong ETag: got %v", etag)
+		}
+	})
+
+	t.Run("it derives different ETags within the same second", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		later := modtime.Add(time.Millisecond)
+		responder.New(rr).Content(nil, "report.txt", later, strings.NewReader(content))
+
+		if rr.Header().Get("ETag") == etag {
+			t.Errorf("expected a different ETag for %v: got %v", later, etag)
+		}
+	})
+
+	t.Run("it returns multipart ranges", func(t *testing.T) {
+		rr := serve(t, map[string]string{"Range": "bytes=0-1,8-9"})
+
+		assertStatusCode(t, http.StatusPartialContent, rr.Code)
+		if !strings.HasPrefix(rr.Header().Get("Content-Type"), "multipart/byteranges") {
+			t.Errorf("handler returned wrong content type: got %v", rr.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("it serves the content without the request", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).Content(nil, "report.txt", modtime, strings.NewReader(content))
+
+		assertOK(t, rr)
+		if rr.Body.String() != content {
+			t.Errorf("handler returned wrong body: got %v want %v", rr.Body.String(), content)
+		}
+	})
+}
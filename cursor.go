@@ -0,0 +1,126 @@
+package responder
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CursorCodec encodes the sort keys of the cursor pagination into opaque
+// tokens signed with HMAC-SHA256, so the clients can't tamper with them
+type CursorCodec struct {
+	key []byte
+	ttl time.Duration
+}
+
+// minCursorKeyLen the shortest key the cursors are signed with
+const minCursorKeyLen = 32
+
+// ErrShortCursorKey is returned when the key of the cursors is too short
+// to keep them from being forged
+var ErrShortCursorKey = errors.New("responder: the cursor key must be at least 32 bytes long")
+
+// NewCursorCodec returns a codec signing the cursors with the secret key,
+// they expire after the ttl unless it is zero. The key must be at least
+// 32 bytes long
+func NewCursorCodec(key []byte, ttl time.Duration) (*CursorCodec, error) {
+	if len(key) < minCursorKeyLen {
+		return nil, ErrShortCursorKey
+	}
+
+	return &CursorCodec{key: key, ttl: ttl}, nil
+}
+
+// cursorPayload the signed content of a cursor
+type cursorPayload struct {
+	Keys      json.RawMessage `json:"k"`
+	ExpiresAt int64           `json:"e,omitempty"`
+}
+
+// Encode returns the token of the sort keys, they are encoded as JSON
+func (codec *CursorCodec) Encode(keys interface{}) (string, error) {
+	raw, err := json.Marshal(keys)
+	if err != nil {
+		return "", err
+	}
+
+	payload := cursorPayload{Keys: raw}
+	if codec.ttl > 0 {
+		payload.ExpiresAt = time.Now().Add(codec.ttl).UnixMilli()
+	}
+
+	content, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(content) + "." + encoding.EncodeToString(codec.sign(content)), nil
+}
+
+// Decode verifies the token and decodes its sort keys into the value
+// pointed to by keys. The invalid and expired tokens are rejected
+// with a ParamError for the cursor parameter
+func (codec *CursorCodec) Decode(token string, keys interface{}) error {
+	invalid := &ParamError{Param: cursorParam, Reason: "is invalid"}
+
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return invalid
+	}
+
+	encoding := base64.RawURLEncoding
+	content, err := encoding.DecodeString(encoded)
+	if err != nil {
+		return invalid
+	}
+
+	mac, err := encoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, codec.sign(content)) {
+		return invalid
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(content, &payload); err != nil {
+		return invalid
+	}
+
+	if payload.ExpiresAt != 0 && time.Now().UnixMilli() > payload.ExpiresAt {
+		return &ParamError{Param: cursorParam, Reason: "has expired"}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload.Keys))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(keys); err != nil {
+		return invalid
+	}
+
+	return nil
+}
+
+// DecodeRequest decodes the cursor query parameter of the request into the
+// value pointed to by keys, it reports false when the request has no cursor
+func (codec *CursorCodec) DecodeRequest(r *http.Request, keys interface{}) (bool, error) {
+	token := r.URL.Query().Get(cursorParam)
+	if token == "" {
+		return false, nil
+	}
+
+	if err := codec.Decode(token, keys); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (codec *CursorCodec) sign(content []byte) []byte {
+	mac := hmac.New(sha256.New, codec.key)
+	mac.Write(content)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,118 @@
+package responder_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin3zra/responder"
+)
+
+type sortKeys struct {
+	Name string `json:"name"`
+	ID   int    `json:"id"`
+}
+
+// secret a cursor key long enough
+var secret = strings.Repeat("s", 32)
+
+func newCursorCodec(t *testing.T, key string, ttl time.Duration) *responder.CursorCodec {
+	codec, err := responder.NewCursorCodec([]byte(key), ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return codec
+}
+
+func TestNewCursorCodec(t *testing.T) {
+	for _, key := range [][]byte{nil, {}, []byte("secret"), []byte(strings.Repeat("s", 31))} {
+		if _, err := responder.NewCursorCodec(key, 0); err != responder.ErrShortCursorKey {
+			t.Errorf("NewCursorCodec returned wrong error for a %d bytes key: got %v want %v", len(key), err, responder.ErrShortCursorKey)
+		}
+	}
+}
+
+func TestCursorCodec(t *testing.T) {
+	codec := newCursorCodec(t, secret, 0)
+
+	t.Run("it round trips the sort keys", func(t *testing.T) {
+		token, err := codec.Encode(sortKeys{Name: "Henry", ID: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.ContainsAny(token, "+/=") {
+			t.Errorf("codec returned a token that isn't URL safe: %s", token)
+		}
+
+		var keys sortKeys
+		if err := codec.Decode(token, &keys); err != nil {
+			t.Fatal(err)
+		}
+
+		if keys != (sortKeys{Name: "Henry", ID: 7}) {
+			t.Errorf("codec returned wrong keys: got %+v", keys)
+		}
+	})
+
+	t.Run("it reads the cursor of the request", func(t *testing.T) {
+		token, _ := codec.Encode(sortKeys{Name: "Henry", ID: 7})
+
+		var keys sortKeys
+		found, err := codec.DecodeRequest(buildPageRequest(t, "http://localhost/customers?cursor="+token), &keys)
+		if err != nil || !found || keys.ID != 7 {
+			t.Errorf("codec returned wrong cursor: got %+v, %v, %v", keys, found, err)
+		}
+
+		found, err = codec.DecodeRequest(buildPageRequest(t, "http://localhost/customers"), &keys)
+		if err != nil || found {
+			t.Errorf("codec returned unexpected cursor: got %v, %v", found, err)
+		}
+	})
+
+	token, _ := codec.Encode(sortKeys{Name: "Henry", ID: 7})
+	encoded, signature, _ := strings.Cut(token, ".")
+	forged, _ := newCursorCodec(t, strings.Repeat("g", 32), 0).Encode(sortKeys{Name: "Henry", ID: 8})
+
+	invalid := []struct {
+		name  string
+		token string
+	}{
+		{"it rejects a token without signature", encoded},
+		{"it rejects a tampered payload", strings.ToUpper(encoded[:1]) + encoded[1:] + "." + signature},
+		{"it rejects a token signed with another key", forged},
+		{"it rejects malformed tokens", "not a cursor"},
+	}
+
+	for _, c := range invalid {
+		t.Run(c.name, func(t *testing.T) {
+			var keys sortKeys
+			err := codec.Decode(c.token, &keys)
+
+			var paramErr *responder.ParamError
+			if !errors.As(err, &paramErr) || paramErr.Param != "cursor" {
+				t.Errorf("codec returned wrong error: got %v want ParamError", err)
+			}
+		})
+	}
+
+	t.Run("it rejects the expired tokens", func(t *testing.T) {
+		expiring := newCursorCodec(t, secret, time.Millisecond)
+		token, _ := expiring.Encode(sortKeys{Name: "Henry", ID: 7})
+		time.Sleep(5 * time.Millisecond)
+
+		var keys sortKeys
+		err := expiring.Decode(token, &keys)
+
+		rr := httptest.NewRecorder()
+		responder.New(rr).Error(err)
+
+		assertBadRequest(t, rr)
+		if description := transform(t, rr)["description"]; description != "cursor has expired" {
+			t.Errorf("handler returned wrong description: got %v want cursor has expired", description)
+		}
+	})
+}
@@ -0,0 +1,241 @@
+package responder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// defaultMaxBodyBytes the size limit of the bodies read by Decode
+const defaultMaxBodyBytes = 1 << 20
+
+// DecodeOption changes how Decode reads the request body
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	maxBytes      int64
+	unknownFields bool
+	mediaTypes    []string
+}
+
+// DecodeMaxBytes sets the size limit of the body, a negative limit reads
+// bodies of any size. It is 1MB by default
+func DecodeMaxBytes(limit int64) DecodeOption {
+	return func(options *decodeOptions) {
+		options.maxBytes = limit
+	}
+}
+
+// DecodeUnknownFields allows the fields the value doesn't know about,
+// they are rejected by default
+func DecodeUnknownFields() DecodeOption {
+	return func(options *decodeOptions) {
+		options.unknownFields = true
+	}
+}
+
+// DecodeMediaTypes sets the media types accepted as the Content-Type of the
+// request, application/json and the ones with a +json suffix by default
+func DecodeMediaTypes(mediaTypes ...string) DecodeOption {
+	return func(options *decodeOptions) {
+		options.mediaTypes = mediaTypes
+	}
+}
+
+// Decode reads the JSON value of the request body. The errors caused by the
+// body are DecodeError, which render a consistent response through Error
+func Decode[T any](r *http.Request, opts ...DecodeOption) (T, error) {
+	var value T
+
+	options := decodeOptions{maxBytes: defaultMaxBodyBytes}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if err := checkContentType(r.Header.Get("Content-Type"), options.mediaTypes); err != nil {
+		return value, err
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return value, emptyBodyError()
+	}
+
+	body := io.Reader(r.Body)
+	if options.maxBytes >= 0 {
+		body = http.MaxBytesReader(nil, r.Body, options.maxBytes)
+	}
+
+	decoder := json.NewDecoder(body)
+	if !options.unknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(&value); err != nil {
+		return value, decodeError(err)
+	}
+
+	// anything but the end of the body after the value is rejected
+	end := decoder.InputOffset()
+	if err := decoder.Decode(&struct{}{}); err != io.EOF {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return value, decodeError(err)
+		}
+
+		return value, &DecodeError{
+			status:      http.StatusBadRequest,
+			message:     "malformed JSON",
+			description: "the body must contain a single JSON value",
+			offset:      end,
+			err:         err,
+		}
+	}
+
+	return value, nil
+}
+
+// checkContentType the error rejecting the Content-Type, nil when it is accepted
+func checkContentType(contentType string, accepted []string) error {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err == nil {
+		if len(accepted) == 0 && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")) {
+			return nil
+		}
+
+		for _, item := range accepted {
+			if strings.EqualFold(mediaType, item) {
+				return nil
+			}
+		}
+	}
+
+	return &DecodeError{
+		status:      http.StatusUnsupportedMediaType,
+		message:     "unsupported media type",
+		description: fmt.Sprintf("the content type %q isn't accepted", contentType),
+		offset:      -1,
+		err:         err,
+	}
+}
+
+func emptyBodyError() *DecodeError {
+	return &DecodeError{
+		status:      http.StatusBadRequest,
+		message:     "empty body",
+		description: "the body must contain a JSON value",
+		offset:      -1,
+	}
+}
+
+// decodeError maps the errors of the JSON decoder into a DecodeError, the
+// ones unrelated to the body, like a broken connection, are kept
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var tooLarge *http.MaxBytesError
+
+	switch {
+	case err == io.EOF:
+		return emptyBodyError()
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &DecodeError{
+			status:      http.StatusBadRequest,
+			message:     "malformed JSON",
+			description: "the body ended unexpectedly",
+			offset:      -1,
+			err:         err,
+		}
+	case errors.As(err, &syntaxErr):
+		return &DecodeError{
+			status:      http.StatusBadRequest,
+			message:     "malformed JSON",
+			description: fmt.Sprintf("%s at offset %d", strings.TrimPrefix(syntaxErr.Error(), "json: "), syntaxErr.Offset),
+			offset:      syntaxErr.Offset,
+			err:         err,
+		}
+	case errors.As(err, &typeErr):
+		return &DecodeError{
+			status:      http.StatusBadRequest,
+			message:     "invalid value",
+			description: fmt.Sprintf("the field %q must be %s, not %s", typeErr.Field, typeErr.Type, typeErr.Value),
+			field:       typeErr.Field,
+			offset:      typeErr.Offset,
+			err:         err,
+		}
+	case errors.As(err, &tooLarge):
+		return &DecodeError{
+			status:      http.StatusRequestEntityTooLarge,
+			message:     "body too large",
+			description: fmt.Sprintf("the body must not exceed %d bytes", tooLarge.Limit),
+			offset:      -1,
+			err:         err,
+		}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// the decoder doesn't have a type for this error
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &DecodeError{
+			status:      http.StatusBadRequest,
+			message:     "unknown field",
+			description: fmt.Sprintf("the field %q isn't known", field),
+			field:       field,
+			offset:      -1,
+			err:         err,
+		}
+	}
+
+	return err
+}
+
+// DecodeError describes a request body Decode couldn't read
+type DecodeError struct {
+	status      int
+	message     string
+	description string
+	field       string
+	offset      int64
+	err         error
+}
+
+// Status the HTTP status code of the error
+func (err *DecodeError) Status() int { return err.status }
+
+// Code the HTTP status code of the error as well
+func (err *DecodeError) Code() int { return err.status }
+
+func (err *DecodeError) Error() string { return err.message }
+
+// Description the detail of what is wrong with the body
+func (err *DecodeError) Description() *string {
+	description := err.description
+	return &description
+}
+
+// InfoURL ...
+func (err *DecodeError) InfoURL() *string { return nil }
+
+// Field the path of the field at fault, empty when it isn't known
+func (err *DecodeError) Field() string { return err.field }
+
+// Offset the offset of the body where the error was found, -1 when it isn't known
+func (err *DecodeError) Offset() int64 { return err.offset }
+
+// Unwrap returns the error reported by the decoder
+func (err *DecodeError) Unwrap() error { return err.err }
+
+// Extensions renders the field and offset at fault
+func (err *DecodeError) Extensions() map[string]interface{} {
+	extensions := make(map[string]interface{})
+	if err.field != "" {
+		extensions["field"] = err.field
+	}
+
+	if err.offset >= 0 {
+		extensions["offset"] = err.offset
+	}
+
+	return extensions
+}
@@ -0,0 +1,140 @@
+package responder_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+type order struct {
+	Customer customer `json:"customer"`
+	Items    []struct {
+		Quantity int `json:"quantity"`
+	} `json:"items"`
+}
+
+func buildBodyRequest(t *testing.T, contentType, body string) *http.Request {
+	req, err := http.NewRequest("POST", "http://localhost/orders", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return req
+}
+
+func TestDecode(t *testing.T) {
+
+	t.Run("it decodes the body", func(t *testing.T) {
+		req := buildBodyRequest(t, "application/json; charset=utf-8", `{"customer": {"name": "Henry"}, "items": [{"quantity": 2}]}`)
+
+		value, err := responder.Decode[order](req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if value.Customer.Name != "Henry" || len(value.Items) != 1 || value.Items[0].Quantity != 2 {
+			t.Errorf("Decode returned wrong value: got %+v", value)
+		}
+	})
+
+	t.Run("it accepts the media types with a json suffix", func(t *testing.T) {
+		req := buildBodyRequest(t, "application/merge-patch+json", `{"name": "Henry"}`)
+
+		if _, err := responder.Decode[customer](req); err != nil {
+			t.Errorf("Decode returned unexpected error: %v", err)
+		}
+	})
+
+	t.Run("it allows unknown fields when asked", func(t *testing.T) {
+		req := buildBodyRequest(t, "application/json", `{"name": "Henry", "age": 42}`)
+
+		if _, err := responder.Decode[customer](req, responder.DecodeUnknownFields()); err != nil {
+			t.Errorf("Decode returned unexpected error: %v", err)
+		}
+	})
+
+	cases := []struct {
+		name        string
+		contentType string
+		body        string
+		opts        []responder.DecodeOption
+		status      int
+		field       string
+		offset      int64
+	}{
+		{"it rejects other content types", "text/plain", `{"name": "Henry"}`, nil, http.StatusUnsupportedMediaType, "", -1},
+		{"it rejects a missing content type", "", `{"name": "Henry"}`, nil, http.StatusUnsupportedMediaType, "", -1},
+		{"it rejects the media types not accepted", "application/json", `{"name": "Henry"}`, []responder.DecodeOption{responder.DecodeMediaTypes("application/vnd.api+json")}, http.StatusUnsupportedMediaType, "", -1},
+		{"it rejects an empty body", "application/json", ``, nil, http.StatusBadRequest, "", -1},
+		{"it rejects malformed JSON", "application/json", `{"name": "Henry",}`, nil, http.StatusBadRequest, "", 18},
+		{"it rejects a truncated body", "application/json", `{"name": "Hen`, nil, http.StatusBadRequest, "", -1},
+		{"it rejects type mismatches", "application/json", `{"items": [{"quantity": "two"}]}`, nil, http.StatusBadRequest, "items.0.quantity", 29},
+		{"it rejects unknown fields", "application/json", `{"customer": {"name": "Henry"}, "total": 3}`, nil, http.StatusBadRequest, "total", -1},
+		{"it rejects several values", "application/json", `{"customer": {"name": "Henry"}} {}`, nil, http.StatusBadRequest, "", 31},
+		{"it rejects oversize bodies", "application/json", `{"customer": {"name": "Henry"}}`, []responder.DecodeOption{responder.DecodeMaxBytes(10)}, http.StatusRequestEntityTooLarge, "", -1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := responder.Decode[order](buildBodyRequest(t, c.contentType, c.body), c.opts...)
+
+			var decodeErr *responder.DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("Decode returned wrong error: got %v want DecodeError", err)
+			}
+
+			if decodeErr.Status() != c.status {
+				t.Errorf("Decode returned wrong status: got %d want %d", decodeErr.Status(), c.status)
+			}
+
+			if decodeErr.Field() != c.field {
+				t.Errorf("Decode returned wrong field: got %q want %q", decodeErr.Field(), c.field)
+			}
+
+			if decodeErr.Offset() != c.offset {
+				t.Errorf("Decode returned wrong offset: got %d want %d", decodeErr.Offset(), c.offset)
+			}
+		})
+	}
+
+	t.Run("it keeps the errors unrelated to the body", func(t *testing.T) {
+		req := buildBodyRequest(t, "application/json", "")
+		req.Body = io.NopCloser(failingReader{})
+
+		_, err := responder.Decode[order](req)
+		if !errors.Is(err, errBrokenConnection) {
+			t.Errorf("Decode returned wrong error: got %v want %v", err, errBrokenConnection)
+		}
+	})
+
+	t.Run("it renders through Error", func(t *testing.T) {
+		_, err := responder.Decode[order](buildBodyRequest(t, "application/json", `{"items": [{"quantity": "two"}]}`))
+
+		rr := httptest.NewRecorder()
+		req := buildRequest(t)
+		responder.New(rr, responder.WithErrorFormat(responder.FormatProblem), responder.WithRequest(req)).Error(err)
+
+		assertBadRequest(t, rr)
+		responseMap := transform(t, rr)
+		if responseMap["field"] != "items.0.quantity" || responseMap["offset"] != float64(29) {
+			t.Errorf("handler returned wrong problem: got %v", responseMap)
+		}
+	})
+}
+
+var errBrokenConnection = errors.New("broken connection")
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errBrokenConnection
+}
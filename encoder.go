@@ -0,0 +1,81 @@
+package responder
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Encoder serializes the payloads written for a media type
+type Encoder interface {
+	// Encode writes the serialized payload into w
+	Encode(w io.Writer, v interface{}) error
+}
+
+// EncoderFunc allow you use an ordinary function as an Encoder
+type EncoderFunc func(w io.Writer, v interface{}) error
+
+// Encode calls f(w, v)
+func (f EncoderFunc) Encode(w io.Writer, v interface{}) error {
+	return f(w, v)
+}
+
+// mediaEncoder an Encoder registered for a media type
+type mediaEncoder struct {
+	mediaType string
+	encoder   Encoder
+}
+
+// defaultEncoders the encoders available out of the box, the first
+// one is used when the request doesn't state any preference
+func defaultEncoders() []mediaEncoder {
+	return []mediaEncoder{
+		{mediaType: "application/json", encoder: JSONEncoder{}},
+	}
+}
+
+// WithEncoder registers the encoder used for the given media type,
+// replacing the one already registered for it. The media types are
+// offered to the client in the order they were registered. Only JSON
+// is available out of the box, XML is enabled with XMLEncoder and the
+// responder doesn't ship any YAML, CBOR or MessagePack encoder, they
+// are supported by registering one for them
+func WithEncoder(mediaType string, encoder Encoder) Option {
+	return func(config *Config) {
+		// the slice may be shared with the package-level configuration
+		encoders := make([]mediaEncoder, 0, len(config.encoders)+1)
+		replaced := false
+		for _, item := range config.encoders {
+			if item.mediaType == mediaType {
+				item.encoder = encoder
+				replaced = true
+			}
+			encoders = append(encoders, item)
+		}
+
+		if !replaced {
+			encoders = append(encoders, mediaEncoder{mediaType: mediaType, encoder: encoder})
+		}
+
+		config.encoders = encoders
+	}
+}
+
+// XMLEncoder encodes the payloads with encoding/xml, preceded by the XML
+// header. The payloads must have a single root element, maps can't be
+// encoded, so it doesn't play along with the envelopes
+type XMLEncoder struct{}
+
+// Encode writes the XML document of v into w
+func (XMLEncoder) Encode(w io.Writer, v interface{}) error {
+	res, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	_, err = w.Write(res)
+	return err
+}
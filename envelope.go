@@ -0,0 +1,69 @@
+package responder
+
+// Envelope describes the document the OK payloads are wrapped in
+type Envelope struct {
+	// DataKey names the member holding the payload
+	DataKey string
+	// FlashKey names the member holding the flash messages,
+	// an empty key leaves the flash section out
+	FlashKey string
+	// MetaKey names the member holding the meta information,
+	// it is left out when there isn't any meta to render
+	MetaKey string
+	// Flashes list the flash messages always present on the flash
+	// section, they are rendered as null when they weren't set
+	Flashes []string
+}
+
+// DefaultEnvelope returns the envelope described on the README
+func DefaultEnvelope() Envelope {
+	return Envelope{
+		DataKey:  "data",
+		FlashKey: "flash",
+		MetaKey:  "meta",
+		Flashes:  []string{"success", "error"},
+	}
+}
+
+// WithEnvelope wraps the OK payloads in the given envelope, the flash
+// messages are rendered on the body instead of being sent as cookies
+func WithEnvelope(envelope Envelope) Option {
+	return func(config *Config) {
+		config.Envelope = &envelope
+	}
+}
+
+// WithoutEnvelope writes the OK payloads as they are
+func WithoutEnvelope() Option {
+	return func(config *Config) {
+		config.Envelope = nil
+	}
+}
+
+func (envelope *Envelope) wrap(payload interface{}, flashes map[string]string, meta map[string]interface{}) map[string]interface{} {
+	dataKey := envelope.DataKey
+	if dataKey == "" {
+		dataKey = "data"
+	}
+
+	document := map[string]interface{}{dataKey: payload}
+
+	if envelope.FlashKey != "" {
+		flash := make(map[string]interface{}, len(envelope.Flashes)+len(flashes))
+		for _, name := range envelope.Flashes {
+			flash[name] = nil
+		}
+
+		for name, value := range flashes {
+			flash[name] = value
+		}
+
+		document[envelope.FlashKey] = flash
+	}
+
+	if envelope.MetaKey != "" && len(meta) > 0 {
+		document[envelope.MetaKey] = meta
+	}
+
+	return document
+}
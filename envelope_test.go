@@ -0,0 +1,113 @@
+package responder_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestEnvelope(t *testing.T) {
+
+	t.Run("it wraps the payload and the flash messages", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithEnvelope(responder.DefaultEnvelope()))
+		respond.With("error", "resource can not be created!").OK(map[string]interface{}{"customer": "Henry"})
+
+		assertOK(t, rr)
+		assertIsJSON(t, rr)
+
+		responseMap := transform(t, rr)
+
+		data, ok := responseMap["data"].(map[string]interface{})
+		if !ok || data["customer"] != "Henry" {
+			t.Errorf("handler returned wrong data: got %v want %v", responseMap["data"], "Henry")
+		}
+
+		flash, ok := responseMap["flash"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("expected key `flash`: got %v", responseMap)
+		}
+
+		if flash["error"] != "resource can not be created!" {
+			t.Errorf("handler returned wrong error flash: got %v", flash["error"])
+		}
+
+		if value, ok := flash["success"]; !ok || value != nil {
+			t.Errorf("handler returned wrong success flash: got %v want nil", value)
+		}
+
+		if _, ok := responseMap["meta"]; ok {
+			t.Errorf("expected key `meta` to be omitted: got %v", responseMap["meta"])
+		}
+
+		if len(rr.Result().Cookies()) != 0 {
+			t.Errorf("expected flash messages not to be sent as cookies")
+		}
+	})
+
+	t.Run("it renders the meta section", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithEnvelope(responder.DefaultEnvelope()))
+		respond.WithMeta("total", 1).OK([]string{"Henry"})
+
+		meta, ok := transform(t, rr)["meta"].(map[string]interface{})
+		if !ok || meta["total"].(float64) != 1 {
+			t.Errorf("handler returned wrong meta: got %v want %v", meta, 1)
+		}
+	})
+
+	t.Run("it uses custom key names", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithEnvelope(responder.Envelope{
+			DataKey:  "result",
+			FlashKey: "messages",
+		}))
+		respond.OK("Henry")
+
+		responseMap := transform(t, rr)
+		if responseMap["result"] != "Henry" {
+			t.Errorf("handler returned wrong data: got %v want %v", responseMap["result"], "Henry")
+		}
+
+		if _, ok := responseMap["messages"]; !ok {
+			t.Errorf("expected key `messages`: got %v", responseMap)
+		}
+	})
+
+	t.Run("it writes the raw payload without an envelope", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr)
+		respond.With("success", "resource created successfully!").OK(map[string]interface{}{"customer": "Henry"})
+
+		responseMap := transform(t, rr)
+		if responseMap["customer"] != "Henry" {
+			t.Errorf("handler returned wrong payload: got %v", responseMap)
+		}
+
+		if rr.Header().Get("X-Flash-Messages") != "resource created successfully!" {
+			t.Errorf("handler returned wrong flash header: got %v", rr.Header().Get("X-Flash-Messages"))
+		}
+	})
+
+	t.Run("it uses the package-level configuration", func(t *testing.T) {
+		responder.Configure(responder.WithEnvelope(responder.DefaultEnvelope()))
+		defer responder.Configure(responder.WithoutEnvelope())
+
+		rr := httptest.NewRecorder()
+		responder.New(rr).OK(nil)
+
+		if _, ok := transform(t, rr)["data"]; !ok {
+			t.Errorf("expected key `data`: got %v", rr.Body.String())
+		}
+
+		rr = httptest.NewRecorder()
+		responder.New(rr, responder.WithoutEnvelope()).OK(nil)
+		assertStatusCode(t, http.StatusOK, rr.Code)
+
+		if rr.Body.String() != "null" {
+			t.Errorf("handler returned wrong body: got %v want null", rr.Body.String())
+		}
+	})
+}
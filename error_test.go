@@ -1,7 +1,10 @@
 package responder_test
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/martin3zra/responder"
@@ -74,3 +77,133 @@ func (badRequest) Code() int {
 func (badRequest) Error() string {
 	return "bad Request"
 }
+
+func TestWrappedErrorFormatter(t *testing.T) {
+	cases := []struct {
+		err          error
+		precedence   responder.Precedence
+		expectStatus int
+		expectCode   float64
+		name         string
+	}{
+		{
+			err:          fmt.Errorf("loading customer: %w", new(notFound)),
+			expectStatus: http.StatusNotFound,
+			expectCode:   5,
+			name:         "it resolves a wrapped formatter",
+		},
+		{
+			err:          fmt.Errorf("handler: %w", fmt.Errorf("service: %w", new(badRequest))),
+			expectStatus: http.StatusBadRequest,
+			expectCode:   3,
+			name:         "it resolves a formatter wrapped several times",
+		},
+		{
+			err:          errors.Join(errors.New("cache miss"), new(notFound)),
+			expectStatus: http.StatusNotFound,
+			expectCode:   5,
+			name:         "it resolves a joined formatter",
+		},
+		{
+			err:          errors.Join(new(badRequest), fmt.Errorf("wrapped: %w", new(notFound))),
+			precedence:   responder.FirstFormatter,
+			expectStatus: http.StatusBadRequest,
+			expectCode:   3,
+			name:         "it picks the first formatter",
+		},
+		{
+			err:          errors.Join(new(badRequest), fmt.Errorf("wrapped: %w", new(notFound))),
+			precedence:   responder.LastFormatter,
+			expectStatus: http.StatusNotFound,
+			expectCode:   5,
+			name:         "it picks the last formatter",
+		},
+		{
+			err:          errors.Join(new(badRequest), new(statusError503), new(notFound)),
+			precedence:   responder.HighestStatus,
+			expectStatus: http.StatusServiceUnavailable,
+			expectCode:   11,
+			name:         "it picks the formatter with the highest status",
+		},
+		{
+			err:          errors.Join(new(notFound), new(statusError503), new(badRequest)),
+			precedence:   responder.LowestStatus,
+			expectStatus: http.StatusBadRequest,
+			expectCode:   3,
+			name:         "it picks the formatter with the lowest status",
+		},
+		{
+			err:          asNotFound{},
+			precedence:   responder.LastFormatter,
+			expectStatus: http.StatusNotFound,
+			expectCode:   5,
+			name:         "it honors the As method",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			responder.New(rr, responder.WithPrecedence(item.precedence)).Error(item.err)
+
+			assertStatusCode(t, item.expectStatus, rr.Code)
+
+			responseMap := transform(t, rr)
+			if responseMap["code"] != item.expectCode {
+				t.Errorf("handler returned wrong code: got %v want %v", responseMap["code"], item.expectCode)
+			}
+		})
+	}
+
+	t.Run("it renders the formatter wrapped in an explicit status", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).Conflict(fmt.Errorf("saving: %w", new(notFound)))
+
+		assertStatusCode(t, http.StatusConflict, rr.Code)
+		if transform(t, rr)["message"] != "resource not found" {
+			t.Errorf("handler returned wrong message: got %v", rr.Body.String())
+		}
+	})
+
+	t.Run("it renders the problem details of a wrapped formatter", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithErrorFormat(responder.FormatProblem)).Error(fmt.Errorf("charging: %w", new(outOfCredit)))
+
+		responseMap := transform(t, rr)
+		if responseMap["title"] != "out of credit" || responseMap["balance"] != float64(30) {
+			t.Errorf("handler returned wrong problem: got %v", responseMap)
+		}
+	})
+}
+
+type statusError503 struct {
+	responder.ErrorDescriptor
+}
+
+func (statusError503) Status() int {
+	return http.StatusServiceUnavailable
+}
+
+func (statusError503) Code() int {
+	return 11
+}
+
+func (statusError503) Error() string {
+	return "service unavailable"
+}
+
+// asNotFound exposes a notFound through its As method only
+type asNotFound struct{}
+
+func (asNotFound) Error() string {
+	return "not found in disguise"
+}
+
+func (asNotFound) As(target interface{}) bool {
+	formatter, ok := target.(*responder.ErrorFormatter)
+	if ok {
+		*formatter = new(notFound)
+	}
+
+	return ok
+}
@@ -0,0 +1,132 @@
+package responder
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"reflect"
+)
+
+// StatusClientClosedRequest the non standard status used when the
+// client went away before the response was ready
+const StatusClientClosedRequest = 499
+
+// Mapping the response an error matching a rule of an ErrorMap is rendered with
+type Mapping struct {
+	// Status the HTTP status code
+	Status int
+	// Code the error code, the status is used when it is zero
+	Code int
+	// Message the short description of the error, the status text
+	// is used when it is empty
+	Message string
+}
+
+// ErrorMap maps the errors not implementing ErrorFormatter, like the
+// sentinel errors of the standard library and third-party packages, to
+// their responses. The rules are checked in the order they were added
+type ErrorMap struct {
+	rules []errorRule
+}
+
+type errorRule struct {
+	match   func(err error) bool
+	mapping Mapping
+}
+
+// NewErrorMap returns an ErrorMap without any rule
+func NewErrorMap() *ErrorMap {
+	return new(ErrorMap)
+}
+
+// DefaultErrorMap returns an ErrorMap with the rules for the errors of the
+// standard library: context.DeadlineExceeded, context.Canceled,
+// os.ErrNotExist and *http.MaxBytesError
+func DefaultErrorMap() *ErrorMap {
+	return NewErrorMap().
+		Is(context.DeadlineExceeded, Mapping{Status: http.StatusGatewayTimeout}).
+		Is(context.Canceled, Mapping{Status: StatusClientClosedRequest, Message: "Client Closed Request"}).
+		Is(os.ErrNotExist, Mapping{Status: http.StatusNotFound}).
+		As(new(*http.MaxBytesError), Mapping{Status: http.StatusRequestEntityTooLarge})
+}
+
+// WithErrorMap sets the ErrorMap consulted by Error before rendering an
+// unexpected server error, nil disables it
+func WithErrorMap(errorMap *ErrorMap) Option {
+	return func(config *Config) {
+		config.ErrorMap = errorMap
+	}
+}
+
+// Is maps the errors matching the target with errors.Is
+func (errorMap *ErrorMap) Is(target error, mapping Mapping) *ErrorMap {
+	return errorMap.Match(func(err error) bool {
+		return errors.Is(err, target)
+	}, mapping)
+}
+
+// As maps the errors matching the target with errors.As, the target must be
+// a non-nil pointer to a type implementing error or to any interface type
+func (errorMap *ErrorMap) As(target interface{}, mapping Mapping) *ErrorMap {
+	targetType := reflect.TypeOf(target)
+	if targetType == nil || targetType.Kind() != reflect.Ptr {
+		panic("Invalid data-type: non-nil pointer expected")
+	}
+
+	return errorMap.Match(func(err error) bool {
+		return errors.As(err, reflect.New(targetType.Elem()).Interface())
+	}, mapping)
+}
+
+// Match maps the errors the predicate returns true for
+func (errorMap *ErrorMap) Match(predicate func(err error) bool, mapping Mapping) *ErrorMap {
+	errorMap.rules = append(errorMap.rules, errorRule{match: predicate, mapping: mapping})
+	return errorMap
+}
+
+// Resolve returns the error rendered with the mapping of the first rule
+// matching the error, false when none of them does
+func (errorMap *ErrorMap) Resolve(err error) (ErrorFormatter, bool) {
+	if errorMap == nil || err == nil {
+		return nil, false
+	}
+
+	for _, rule := range errorMap.rules {
+		if rule.match(err) {
+			return &mappedError{mapping: rule.mapping, err: err}, true
+		}
+	}
+
+	return nil, false
+}
+
+// mappedError an error rendered with the mapping of an ErrorMap
+type mappedError struct {
+	mapping Mapping
+	err     error
+}
+
+func (err *mappedError) Status() int { return err.mapping.Status }
+
+func (err *mappedError) Code() int {
+	if err.mapping.Code == 0 {
+		return err.mapping.Status
+	}
+
+	return err.mapping.Code
+}
+
+func (err *mappedError) Error() string {
+	if err.mapping.Message == "" {
+		return http.StatusText(err.mapping.Status)
+	}
+
+	return err.mapping.Message
+}
+
+func (err *mappedError) Description() *string { return nil }
+
+func (err *mappedError) InfoURL() *string { return nil }
+
+func (err *mappedError) Unwrap() error { return err.err }
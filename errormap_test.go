@@ -0,0 +1,136 @@
+package responder_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestDefaultErrorMap(t *testing.T) {
+	_, notExist := os.Open("does-not-exist.json")
+
+	body := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader("too large")), 2)
+	_, tooLarge := io.ReadAll(body)
+
+	cases := []struct {
+		err           error
+		expectStatus  int
+		expectMessage string
+		name          string
+	}{
+		{
+			err:           fmt.Errorf("querying: %w", context.DeadlineExceeded),
+			expectStatus:  http.StatusGatewayTimeout,
+			expectMessage: http.StatusText(http.StatusGatewayTimeout),
+			name:          "it maps context.DeadlineExceeded",
+		},
+		{
+			err:           context.Canceled,
+			expectStatus:  responder.StatusClientClosedRequest,
+			expectMessage: "Client Closed Request",
+			name:          "it maps context.Canceled",
+		},
+		{
+			err:           notExist,
+			expectStatus:  http.StatusNotFound,
+			expectMessage: http.StatusText(http.StatusNotFound),
+			name:          "it maps os.ErrNotExist",
+		},
+		{
+			err:           tooLarge,
+			expectStatus:  http.StatusRequestEntityTooLarge,
+			expectMessage: http.StatusText(http.StatusRequestEntityTooLarge),
+			name:          "it maps http.MaxBytesError",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			responder.New(rr).Error(item.err)
+
+			assertStatusCode(t, item.expectStatus, rr.Code)
+
+			responseMap := transform(t, rr)
+			if responseMap["message"] != item.expectMessage || responseMap["code"] != float64(item.expectStatus) {
+				t.Errorf("handler returned wrong error: got %v", responseMap)
+			}
+		})
+	}
+}
+
+func TestErrorMap(t *testing.T) {
+	errorMap := responder.NewErrorMap().
+		Is(sql.ErrNoRows, responder.Mapping{Status: http.StatusNotFound, Code: 40401, Message: "record not found"}).
+		As(new(*os.PathError), responder.Mapping{Status: http.StatusServiceUnavailable}).
+		Match(func(err error) bool {
+			return strings.Contains(err.Error(), "duplicate key")
+		}, responder.Mapping{Status: http.StatusConflict, Code: 40901}).
+		Is(sql.ErrNoRows, responder.Mapping{Status: http.StatusGone})
+
+	cases := []struct {
+		err          error
+		expectStatus int
+		expectCode   float64
+		name         string
+	}{
+		{
+			err:          fmt.Errorf("finding customer: %w", sql.ErrNoRows),
+			expectStatus: http.StatusNotFound,
+			expectCode:   40401,
+			name:         "it maps the errors.Is targets by the first rule",
+		},
+		{
+			err:          &os.PathError{Op: "open", Path: "/tmp", Err: errors.New("busy")},
+			expectStatus: http.StatusServiceUnavailable,
+			expectCode:   http.StatusServiceUnavailable,
+			name:         "it maps the errors.As types",
+		},
+		{
+			err:          errors.New(`pq: duplicate key value violates unique constraint "customers_email_key"`),
+			expectStatus: http.StatusConflict,
+			expectCode:   40901,
+			name:         "it maps the predicates",
+		},
+		{
+			err:          fmt.Errorf("wrapped: %w", new(notFound)),
+			expectStatus: http.StatusNotFound,
+			expectCode:   5,
+			name:         "it prefers the ErrorFormatter",
+		},
+		{
+			err:          errors.New("some error"),
+			expectStatus: http.StatusInternalServerError,
+			expectCode:   http.StatusInternalServerError,
+			name:         "it falls back to the internal server error",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			responder.New(rr, responder.WithErrorMap(errorMap), responder.WithErrorLogger(nil)).Error(item.err)
+
+			assertStatusCode(t, item.expectStatus, rr.Code)
+			if code := transform(t, rr)["code"]; code != item.expectCode {
+				t.Errorf("handler returned wrong code: got %v want %v", code, item.expectCode)
+			}
+		})
+	}
+
+	t.Run("it can be disabled", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithErrorMap(nil), responder.WithErrorLogger(nil)).Error(context.Canceled)
+
+		assertInternalError(t, rr)
+	})
+}
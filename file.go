@@ -0,0 +1,149 @@
+package responder
+
+import (
+	"bufio"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Disposition how the client presents a file
+type Disposition int
+
+const (
+	// Attachment the client downloads the file
+	Attachment Disposition = iota
+	// Inline the client displays the file when it can
+	Inline
+)
+
+// sniffLen the bytes http.DetectContentType looks at
+const sniffLen = 512
+
+// File writes the content as a file, the content type is detected from
+// the extension of the name or the content itself when it's empty.
+// The content implementing io.Seeker is served with range support
+func (response *HttpResponse) File(content io.Reader, name string, disposition Disposition, contentType string) {
+	header := response.writer.Header()
+	header.Set("Content-Disposition", contentDisposition(disposition, name))
+
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(name))
+	}
+
+	if seeker, ok := content.(io.ReadSeeker); ok {
+		if contentType != "" {
+			header.Set("Content-Type", contentType)
+		}
+
+		// http.ServeContent sniffs the content type when it isn't set
+		response.Content(nil, name, time.Time{}, seeker)
+		return
+	}
+
+	body := bufio.NewReaderSize(content, sniffLen)
+	if contentType == "" {
+		sniff, err := body.Peek(sniffLen)
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			header.Del("Content-Disposition")
+			response.InternalServerError(err)
+			return
+		}
+
+		contentType = http.DetectContentType(sniff)
+	}
+
+	header.Set("Content-Type", contentType)
+	response.writer.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(response.writer, body); err != nil {
+		if !response.writer.reset() {
+			// the client must not take a truncated file as a complete one
+			panic(http.ErrAbortHandler)
+		}
+
+		header.Del("Content-Disposition")
+		response.InternalServerError(err)
+		return
+	}
+
+	response.writer.commit()
+}
+
+// contentDisposition formats the header as RFC 6266 describes, the names
+// that aren't plain ASCII are encoded as RFC 5987 describes and an ASCII
+// fallback is kept for the clients not supporting it
+func contentDisposition(disposition Disposition, name string) string {
+	value := "attachment"
+	if disposition == Inline {
+		value = "inline"
+	}
+
+	name = filepath.Base(filepath.ToSlash(name))
+	if name == "" || name == "." || name == "/" {
+		return value
+	}
+
+	fallback, plain := asciiFallback(name)
+	value += `; filename="` + fallback + `"`
+	if !plain {
+		value += "; filename*=UTF-8''" + extValueEscape(name)
+	}
+
+	return value
+}
+
+// asciiFallback replaces the characters that can't travel on a quoted
+// string, it reports whether the name was kept as it is
+func asciiFallback(name string) (string, bool) {
+	var fallback strings.Builder
+	plain := true
+
+	for _, char := range name {
+		switch {
+		case char == '"' || char == '\\':
+			fallback.WriteRune('\\')
+			fallback.WriteRune(char)
+		case char < 0x20 || char > 0x7e:
+			fallback.WriteRune('_')
+			plain = false
+		default:
+			fallback.WriteRune(char)
+		}
+	}
+
+	return fallback.String(), plain
+}
+
+// extValueEscape percent-encodes the bytes that aren't an RFC 5987 attr-char
+func extValueEscape(value string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		char := value[i]
+		if isAttrChar(char) {
+			escaped.WriteByte(char)
+			continue
+		}
+
+		escaped.WriteByte('%')
+		if char < 0x10 {
+			escaped.WriteByte('0')
+		}
+		escaped.WriteString(strings.ToUpper(strconv.FormatUint(uint64(char), 16)))
+	}
+
+	return escaped.String()
+}
+
+func isAttrChar(char byte) bool {
+	switch {
+	case 'a' <= char && char <= 'z', 'A' <= char && char <= 'Z', '0' <= char && char <= '9':
+		return true
+	}
+
+	return strings.IndexByte("!#$&+-.^_`|~", char) >= 0
+}
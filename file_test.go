@@ -0,0 +1,114 @@
+package responder_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestFile(t *testing.T) {
+	cases := []struct {
+		content           string
+		fileName          string
+		disposition       responder.Disposition
+		contentType       string
+		seekable          bool
+		expectContentType string
+		expectDisposition string
+		name              string
+	}{
+		{
+			content:           "name,total",
+			fileName:          "report.csv",
+			disposition:       responder.Attachment,
+			expectContentType: "text/csv; charset=utf-8",
+			expectDisposition: `attachment; filename="report.csv"`,
+			name:              "it detects the content type from the extension",
+		},
+		{
+			content:           "%PDF-1.7",
+			fileName:          "invoice",
+			disposition:       responder.Inline,
+			expectContentType: "application/pdf",
+			expectDisposition: `inline; filename="invoice"`,
+			name:              "it detects the content type from the content",
+		},
+		{
+			content:           "%PDF-1.7",
+			fileName:          "invoice",
+			seekable:          true,
+			expectContentType: "application/pdf",
+			expectDisposition: `attachment; filename="invoice"`,
+			name:              "it detects the content type from the seekable content",
+		},
+		{
+			content:           "{}",
+			fileName:          "data.bin",
+			contentType:       "application/json",
+			expectContentType: "application/json",
+			expectDisposition: `attachment; filename="data.bin"`,
+			name:              "it uses the given content type",
+		},
+		{
+			content:           "total",
+			fileName:          "reporte año 2023.txt",
+			expectContentType: "text/plain; charset=utf-8",
+			expectDisposition: `attachment; filename="reporte a_o 2023.txt"; filename*=UTF-8''reporte%20a%C3%B1o%202023.txt`,
+			name:              "it encodes the UTF-8 names",
+		},
+		{
+			content:           "total",
+			fileName:          `../say "hi".txt`,
+			expectContentType: "text/plain; charset=utf-8",
+			expectDisposition: `attachment; filename="say \"hi\".txt"`,
+			name:              "it escapes the quotes and drops the directories",
+		},
+		{
+			content:           "total",
+			disposition:       responder.Inline,
+			expectContentType: "text/plain; charset=utf-8",
+			expectDisposition: "inline",
+			name:              "it omits the missing name",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			var content io.Reader = strings.NewReader(item.content)
+			if !item.seekable {
+				content = struct{ io.Reader }{content}
+			}
+
+			rr := httptest.NewRecorder()
+			responder.New(rr).File(content, item.fileName, item.disposition, item.contentType)
+
+			assertOK(t, rr)
+			assertContentType(t, rr, item.expectContentType)
+			if rr.Header().Get("Content-Disposition") != item.expectDisposition {
+				t.Errorf("handler returned wrong disposition: got %v want %v",
+					rr.Header().Get("Content-Disposition"), item.expectDisposition)
+			}
+
+			if rr.Body.String() != item.content {
+				t.Errorf("handler returned wrong body: got %v want %v", rr.Body.String(), item.content)
+			}
+		})
+	}
+
+	t.Run("it serves ranges of the seekable content", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Range", "bytes=0-3")
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithRequest(req)).File(strings.NewReader("%PDF-1.7"), "invoice.pdf", responder.Inline, "")
+
+		assertStatusCode(t, http.StatusPartialContent, rr.Code)
+		if rr.Body.String() != "%PDF" {
+			t.Errorf("handler returned wrong body: got %v want %v", rr.Body.String(), "%PDF")
+		}
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/martin3zra/responder
 
-go 1.16
+go 1.21
@@ -0,0 +1,125 @@
+package responder
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Result describes the response of a handler adapted by Handle when the
+// payload alone isn't enough
+type Result struct {
+	// Status the HTTP status code, zero responds with http.StatusOK or with
+	// http.StatusNoContent when there's no payload
+	Status int
+	// Payload the body of the response
+	Payload interface{}
+	// Header the headers added to the response
+	Header http.Header
+	// Flash the flash messages sent with the response
+	Flash map[string]string
+	// Resource the identifier of the resource created, the location of the
+	// http.StatusCreated responses is built with it like Created does
+	Resource interface{}
+}
+
+// Handle adapts a handler returning its payload instead of writing it. The
+// error is rendered by Error, a nil payload responds with http.StatusNoContent
+// and any other with http.StatusOK unless it is a Result. The responses are
+// written to the writer given to the handler, configured like the Respond of
+// the Middleware when there's one and with the options on top
+func Handle(handler func(r *http.Request) (interface{}, error), opts ...Option) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond := handlerRespond(w, r, opts)
+
+		payload, err := handler(r)
+		if err != nil {
+			respond.Error(err)
+			return
+		}
+
+		respond.result(payload)
+	})
+}
+
+// handlerRespond the Respond writing to w, it is configured like the one of
+// the Middleware when there's one, plus the options
+func handlerRespond(w http.ResponseWriter, r *http.Request, opts []Option) *Respond {
+	parent := From(r)
+	if parent == nil {
+		return NewWithRequest(w, r, opts...)
+	}
+
+	config := parent.response.config
+	for _, opt := range opts {
+		opt(&config)
+	}
+	config.request = r
+
+	return &Respond{w: w, response: newHttpResponse(w, make(map[string]string), config)}
+}
+
+// HandleTyped adapts a handler returning a typed payload like Handle does
+func HandleTyped[T any](handler func(r *http.Request) (T, error), opts ...Option) http.Handler {
+	return Handle(func(r *http.Request) (interface{}, error) {
+		return handler(r)
+	}, opts...)
+}
+
+// result responds with the payload returned by a handler
+func (res *Respond) result(payload interface{}) {
+	var result Result
+	switch value := payload.(type) {
+	case Result:
+		result = value
+	case *Result:
+		if value != nil {
+			result = *value
+		}
+	default:
+		result.Payload = payload
+	}
+
+	header := res.response.writer.Header()
+	for name, values := range result.Header {
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+
+	if len(result.Flash) > 0 {
+		if res.response.attributes == nil {
+			res.response.attributes = make(map[string]string, len(result.Flash))
+		}
+
+		for name, value := range result.Flash {
+			res.response.attributes[name] = value
+		}
+	}
+
+	empty := isNil(result.Payload)
+	switch {
+	case result.Status == http.StatusCreated && result.Resource != nil && empty:
+		res.Created(nil, result.Resource)
+	case result.Status == http.StatusCreated && result.Resource != nil:
+		header.Set("Location", res.response.buildLocationURL(res.response.config.request, result.Resource))
+		res.response.asPayload(http.StatusCreated, result.Payload)
+	case result.Status == 0 && empty, result.Status == http.StatusNoContent:
+		res.NoContent()
+	case result.Status == 0:
+		res.OK(result.Payload)
+	case empty:
+		res.response.asEmpty(result.Status)
+	default:
+		res.response.asPayload(result.Status, result.Payload)
+	}
+}
+
+// isNil reports whether the payload is nil or a nil pointer
+func isNil(payload interface{}) bool {
+	if payload == nil {
+		return true
+	}
+
+	value := reflect.ValueOf(payload)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
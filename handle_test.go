@@ -0,0 +1,188 @@
+package responder_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestHandle(t *testing.T) {
+
+	cases := []struct {
+		name     string
+		handler  func(r *http.Request) (interface{}, error)
+		status   int
+		location string
+		body     string
+	}{
+		{
+			name:    "it responds OK with the payload",
+			handler: func(r *http.Request) (interface{}, error) { return customer{Name: "Henry"}, nil },
+			status:  http.StatusOK,
+			body:    `{"name":"Henry"}`,
+		},
+		{
+			name:    "it responds no content without payload",
+			handler: func(r *http.Request) (interface{}, error) { return nil, nil },
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "it responds no content with a nil pointer",
+			handler: func(r *http.Request) (interface{}, error) { return (*customer)(nil), nil },
+			status:  http.StatusNoContent,
+		},
+		{
+			name:    "it renders the error",
+			handler: func(r *http.Request) (interface{}, error) { return customer{}, new(notFound) },
+			status:  http.StatusNotFound,
+		},
+		{
+			name: "it responds created with the location of the resource",
+			handler: func(r *http.Request) (interface{}, error) {
+				return responder.Result{Status: http.StatusCreated, Resource: 7}, nil
+			},
+			status:   http.StatusCreated,
+			location: "http://localhost/ok/7",
+		},
+		{
+			name: "it responds created with a body",
+			handler: func(r *http.Request) (interface{}, error) {
+				return &responder.Result{Status: http.StatusCreated, Resource: 7, Payload: customer{Name: "Henry"}}, nil
+			},
+			status:   http.StatusCreated,
+			location: "http://localhost/ok/7",
+			body:     `{"name":"Henry"}`,
+		},
+		{
+			name: "it responds with a custom status",
+			handler: func(r *http.Request) (interface{}, error) {
+				return responder.Result{Status: http.StatusAccepted, Payload: customer{Name: "Henry"}}, nil
+			},
+			status: http.StatusAccepted,
+			body:   `{"name":"Henry"}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rr := newStrictRecorder(t)
+			responder.Handle(c.handler).ServeHTTP(rr, buildRequest(t))
+
+			assertStatusCode(t, c.status, rr.Code)
+			if location := rr.Header().Get("Location"); location != c.location {
+				t.Errorf("handler returned wrong location: got %q want %q", location, c.location)
+			}
+
+			if c.body != "" && rr.Body.String() != c.body {
+				t.Errorf("handler returned wrong body: got %s want %s", rr.Body.String(), c.body)
+			}
+		})
+	}
+
+	t.Run("it sends the headers and flash messages of the result", func(t *testing.T) {
+		handler := responder.Handle(func(r *http.Request) (interface{}, error) {
+			return responder.Result{
+				Payload: customer{Name: "Henry"},
+				Header:  http.Header{"X-Request-Id": {"42"}},
+				Flash:   map[string]string{"success": "customer updated"},
+			}, nil
+		})
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertOK(t, rr)
+		if id := rr.Header().Get("X-Request-Id"); id != "42" {
+			t.Errorf("handler returned wrong header: got %q want %q", id, "42")
+		}
+
+		if flash := rr.Header().Get("X-Flash-Messages"); flash != "customer updated" {
+			t.Errorf("handler returned wrong flash: got %q want %q", flash, "customer updated")
+		}
+	})
+
+	t.Run("it uses the configuration of the middleware", func(t *testing.T) {
+		handler := responder.Middleware(responder.WithErrorFormat(responder.FormatProblem))(
+			responder.Handle(func(r *http.Request) (interface{}, error) {
+				return nil, new(notFound)
+			}),
+		)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertNotFound(t, rr)
+		assertIsProblem(t, rr)
+	})
+
+	t.Run("it writes to the writers wrapped behind the middleware", func(t *testing.T) {
+		var status int
+		recordStatus := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				recorder := &statusRecorder{ResponseWriter: w}
+				next.ServeHTTP(recorder, r)
+				status = recorder.status
+			})
+		}
+
+		handler := responder.Middleware(responder.WithEnvelope(responder.DefaultEnvelope()))(
+			recordStatus(responder.Handle(func(r *http.Request) (interface{}, error) {
+				return responder.Result{Status: http.StatusAccepted, Payload: customer{Name: "Henry"}}, nil
+			}, responder.WithoutEnvelope())),
+		)
+
+		rr := newStrictRecorder(t)
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertStatusCode(t, http.StatusAccepted, rr.Code)
+		if status != http.StatusAccepted {
+			t.Errorf("wrapped writer got wrong status: got %d want %d", status, http.StatusAccepted)
+		}
+
+		if rr.Body.String() != `{"name":"Henry"}` {
+			t.Errorf("handler ignored the options: got %s", rr.Body.String())
+		}
+	})
+}
+
+// statusRecorder a middleware writer recording the status of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(statusCode int) {
+	w.status = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func TestHandleTyped(t *testing.T) {
+
+	t.Run("it responds OK with the typed payload", func(t *testing.T) {
+		handler := responder.HandleTyped(func(r *http.Request) (*customer, error) {
+			return &customer{Name: "Henry"}, nil
+		})
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertOK(t, rr)
+		if name := transform(t, rr)["name"]; name != "Henry" {
+			t.Errorf("handler returned wrong name: got %v want Henry", name)
+		}
+	})
+
+	t.Run("it renders the error", func(t *testing.T) {
+		handler := responder.HandleTyped(func(r *http.Request) (*customer, error) {
+			return nil, errors.New("database unavailable")
+		}, responder.WithErrorLogger(func(responder.ErrorLog) {}))
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertInternalError(t, rr)
+	})
+}
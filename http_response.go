@@ -3,20 +3,22 @@ package responder
 import (
 	"bytes"
 	"encoding/base64"
-	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 	"reflect"
 	"time"
 )
 
-func newHttpResponse(w http.ResponseWriter, attributes map[string]string) *HttpResponse {
-	return &HttpResponse{writer: w, attributes: attributes}
+func newHttpResponse(w http.ResponseWriter, attributes map[string]string, config Config) *HttpResponse {
+	return &HttpResponse{writer: newPipeline(w, config.BufferLimit), attributes: attributes, config: config}
 }
 
 type HttpResponse struct {
-	writer     http.ResponseWriter
+	writer     *pipeline
 	attributes map[string]string
+	meta       map[string]interface{}
+	config     Config
 }
 
 func (response *HttpResponse) setAttributes(attributes map[string]string) *HttpResponse {
@@ -24,6 +26,17 @@ func (response *HttpResponse) setAttributes(attributes map[string]string) *HttpR
 	return response
 }
 
+// WithMeta allow you set a meta value, it is rendered on the meta
+// section of the envelope and ignored when there isn't one
+func (response *HttpResponse) WithMeta(key string, value interface{}) *HttpResponse {
+	if response.meta == nil {
+		response.meta = make(map[string]interface{})
+	}
+
+	response.meta[key] = value
+	return response
+}
+
 // emptyStatus a collection of http status code doesn't need a body as response
 func (response *HttpResponse) emptyStatus() []int {
 	return []int{
@@ -35,143 +48,286 @@ func (response *HttpResponse) emptyStatus() []int {
 
 // OK respond with http.StatusOK
 func (response *HttpResponse) OK(payload interface{}) {
+	response.asPayload(http.StatusOK, payload)
+}
 
-	res, err := json.Marshal(payload)
-	if err != nil {
-		response.writer.WriteHeader(http.StatusInternalServerError)
-		response.writer.Write([]byte(err.Error()))
+func (response *HttpResponse) asPayload(statusCode int, payload interface{}) {
+	response.asEnveloped(statusCode, payload, response.config.Envelope, response.meta)
+}
+
+// asEnveloped responds with the payload wrapped in the envelope along with the meta
+func (response *HttpResponse) asEnveloped(statusCode int, payload interface{}, envelope *Envelope, meta map[string]interface{}) {
+	offer, ok := response.negotiate()
+	if !ok {
+		response.notAcceptable()
 		return
 	}
 
-	response.asJSON(http.StatusOK, []byte(res))
+	if envelope != nil {
+		payload = envelope.wrap(payload, response.attributes, meta)
+	} else {
+		// the flash messages travel on the body when there's an envelope
+		response.registerAttributes()
+	}
+
+	if err := response.encode(statusCode, offer.mediaType, offer.encoder, payload); err != nil {
+		response.renderError(http.StatusInternalServerError, err)
+	}
 }
 
 // NoContent ...
 func (response *HttpResponse) NoContent() {
-	response.asJSON(http.StatusNoContent, nil)
+	response.asEmpty(http.StatusNoContent)
 }
 
-// Created ...
+// Created responds with http.StatusCreated and the location of the resource,
+// the request being responded is used when r is nil
 func (response *HttpResponse) Created(r *http.Request, resource interface{}) {
+	if r == nil {
+		r = response.config.request
+	}
+
 	response.writer.Header().Set("Location", response.buildLocationURL(r, resource))
-	response.asJSON(http.StatusCreated, nil)
+	response.asEmpty(http.StatusCreated)
 }
 
-// Plain ...
+// Plain downloads the stream as a plain text file
 func (response *HttpResponse) Plain(stream []byte, fileName string) {
-	response.writer.Header().Set("content-disposition", "attachment; filename=\""+fileName+"\"")
-	response.file(stream, "application/plain")
+	response.File(bytes.NewReader(stream), fileName, Attachment, "application/plain")
 }
 
-// PDF ...
+// PDF displays the stream as a PDF file
 func (response *HttpResponse) PDF(stream []byte) {
-	response.file(stream, "application/pdf")
+	response.File(bytes.NewReader(stream), "", Inline, "application/pdf")
 }
 
-// Excel ...
+// Excel downloads the stream as an Excel workbook
 func (response *HttpResponse) Excel(stream []byte) {
-	response.writer.WriteHeader(http.StatusOK)
-	// stream straight to client(browser)
-	response.writer.Header().Set("Content-Description", "File Transfer")
-	response.writer.Header().Set("Content-Disposition", "attachment;")
-	response.writer.Header().Set("Content-type", "application/octet-stream")
-	b := bytes.NewBuffer(stream)
-
-	if _, err := b.WriteTo(response.writer); err != nil {
-		response.writer.WriteHeader(http.StatusInternalServerError)
-		response.writer.Write([]byte(err.Error()))
-		return
-	}
-
-	response.writer.Write([]byte(""))
+	response.File(bytes.NewReader(stream), "", Attachment, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
 }
 
 // NotFound is returned when the resource requested by your application does not exist
 func (response *HttpResponse) NotFound(err error) {
-	response.asJSON(http.StatusNotFound, response.getMessage(err))
+	response.asError(http.StatusNotFound, err)
 }
 
 // Unauthorized is returned when there is a problem with the credentials provided by your application.
 // This code indicates that your application tried to operate on a protected resource without
 // providing the proper authorization. It may have provided the wrong credentials or none at all
 func (response *HttpResponse) Unauthorized(err error) {
-	response.asJSON(http.StatusUnauthorized, response.getMessage(err))
+	response.asError(http.StatusUnauthorized, err)
 }
 
 // Forbidden is returned when your application is not authorized to access the requested resource,
 // or when your application is being rate limited
 func (response *HttpResponse) Forbidden(err error) {
-	response.asJSON(http.StatusForbidden, response.getMessage(err))
+	response.asError(http.StatusForbidden, err)
 }
 
 // BadRequest is returned when the request entity sent by your application could not
 // be understood by the server due to malformed syntax (e.g. invalid payload, data type mismatch)
 func (response *HttpResponse) BadRequest(err error) {
-	response.asJSON(http.StatusBadRequest, response.getMessage(err))
+	response.asError(http.StatusBadRequest, err)
 }
 
 // UnprocessableEntity ...
 func (response *HttpResponse) UnprocessableEntity(err error) {
-	response.asJSON(http.StatusUnprocessableEntity, response.getMessage(err))
+	response.asError(http.StatusUnprocessableEntity, err)
 }
 
 // Conflict is returned when the request sent by your application could not be completed due to a conflict
 // with the current state of the resource
 func (response *HttpResponse) Conflict(err error) {
-	response.asJSON(http.StatusConflict, response.getMessage(err))
+	response.asError(http.StatusConflict, err)
 }
 
 // Error is returned when the server encountered an unexpected condition which prevented it from fulfilling
 // the request sent by your application
 func (response *HttpResponse) InternalServerError(err error) {
-	response.writer.Header().Set("Content-Type", "application/json")
-	response.writer.WriteHeader(http.StatusInternalServerError)
-	if err == nil {
-		return
-	}
-	response.writer.Write([]byte(err.Error()))
+	response.asError(http.StatusInternalServerError, err)
 }
 
-// Error is returned when the server encountered an unexpected condition which prevented it from fulfilling
-// the request sent by your application
+// Error responds with the ErrorFormatter wrapped by the error, or with the mapping of the ErrorMap
+// matching it. Otherwise the server encountered an unexpected condition which prevented it from
+// fulfilling the request sent by your application
 func (response *HttpResponse) Error(err error) {
 
-	errValue, ok := err.(ErrorFormatter)
-	if !ok {
-		response.InternalServerError(err)
+	if errValue, ok := response.resolve(err); ok {
+		response.asError(errorStatus(errValue), err)
+		return
+	}
+
+	if mapped, ok := response.config.ErrorMap.Resolve(err); ok {
+		response.asError(errorStatus(mapped), mapped)
 		return
 	}
 
-	response.composeCustomError(errValue)
+	response.InternalServerError(err)
 }
 
-func (response *HttpResponse) asJSON(statusCode int, stream []byte) {
+func (response *HttpResponse) asEmpty(statusCode int) {
 	response.registerAttributes()
-	response.writer.Header().Set("Content-Type", "application/json")
-	response.writer.WriteHeader(statusCode)
+	response.write(statusCode, response.negotiateOrDefault().mediaType, nil)
+}
 
-	if response.doesNotRequireContent(statusCode) {
+func (response *HttpResponse) asError(statusCode int, err error) {
+	response.registerAttributes()
+	response.renderError(statusCode, err)
+}
+
+// renderError errors are always rendered, the first encoder is used
+// when none of them is acceptable
+func (response *HttpResponse) renderError(statusCode int, err error) {
+	offer := response.negotiateOrDefault()
+
+	var value ErrorFormatter
+	if err != nil {
+		value = response.formatter(statusCode, err)
+	}
+
+	translation, language, translated := response.translate(value)
+	if translated {
+		response.writer.Header().Set("Content-Language", language)
+	}
+
+	var body interface{}
+	mediaType := offer.mediaType
+	switch {
+	case response.config.ErrorFormat == FormatProblem:
+		var problemErr error
+		if value != nil {
+			problemErr = value
+		}
+
+		problem := NewProblem(statusCode, problemErr, response.config.request)
+		if translated {
+			problem.Title = translation.Message
+			if translation.Description != "" {
+				problem.Detail = translation.Description
+			}
+		}
+
+		body = problem
+		mediaType = problemMediaType(mediaType)
+	case value == nil:
+		response.write(statusCode, mediaType, nil)
+		response.report(statusCode, err, value)
 		return
+	default:
+		message := newErrorMessage(value)
+		if translated {
+			message.Message = translation.Message
+			if translation.Description != "" {
+				message.Description = &translation.Description
+			}
+		}
+
+		body = message
 	}
 
-	if stream == nil {
+	encodeErr := response.encode(statusCode, mediaType, offer.encoder, body)
+	if encodeErr == nil {
+		response.report(statusCode, err, value)
 		return
 	}
 
-	response.writer.Write(stream)
+	if _, internal := value.(*internalError); internal {
+		// there's nothing simpler left to render
+		response.writer.WriteHeader(http.StatusInternalServerError)
+		response.writer.commit()
+		response.report(http.StatusInternalServerError, err, value)
+		return
+	}
+
+	response.renderError(http.StatusInternalServerError, encodeErr)
 }
 
-func (response *HttpResponse) file(stream []byte, contentType string) {
-	response.writer.WriteHeader(http.StatusOK)
-	// stream straight to client(browser)
-	response.writer.Header().Set("Content-type", contentType)
-	b := bytes.NewBuffer(stream)
+// encode buffers the body up to the configured limit and streams the
+// rest of it. The encoding error is returned while nothing was sent
+// to the client, afterwards the response is aborted
+func (response *HttpResponse) encode(statusCode int, mediaType string, encoder Encoder, payload interface{}) error {
+	response.writer.Header().Set("Content-Type", mediaType)
+	response.writer.WriteHeader(statusCode)
 
-	if _, err := b.WriteTo(response.writer); err != nil {
-		response.writer.WriteHeader(http.StatusInternalServerError)
-		response.writer.Write([]byte(err.Error()))
+	if err := encoder.Encode(response.writer, payload); err != nil {
+		if !response.writer.reset() {
+			// the client must not take a truncated body as a complete one
+			panic(http.ErrAbortHandler)
+		}
+
+		return err
+	}
+
+	response.writer.commit()
+	return nil
+}
+
+// negotiate picks the encoder for the Accept header of the request,
+// the first encoder is used when the request isn't known
+func (response *HttpResponse) negotiate() (mediaEncoder, bool) {
+	encoders := response.config.encoders
+	if len(encoders) == 0 {
+		return mediaEncoder{}, false
+	}
+
+	if response.config.request == nil {
+		return encoders[0], true
+	}
+
+	vary(response.writer.Header(), "Accept")
+
+	offers := make([]string, len(encoders))
+	for i, item := range encoders {
+		offers[i] = item.mediaType
+	}
+
+	mediaType, ok := negotiate(response.config.request.Header.Get("Accept"), offers)
+	if !ok {
+		return mediaEncoder{}, false
+	}
+
+	for _, item := range encoders {
+		if item.mediaType == mediaType {
+			return item, true
+		}
+	}
+
+	return mediaEncoder{}, false
+}
+
+func (response *HttpResponse) negotiateOrDefault() mediaEncoder {
+	if offer, ok := response.negotiate(); ok {
+		return offer
+	}
+
+	if len(response.config.encoders) > 0 {
+		return response.config.encoders[0]
+	}
+
+	return defaultEncoders()[0]
+}
+
+func (response *HttpResponse) notAcceptable() {
+	response.writer.WriteHeader(http.StatusNotAcceptable)
+	response.writer.commit()
+	response.report(http.StatusNotAcceptable, nil, nil)
+}
+
+func (response *HttpResponse) write(statusCode int, contentType string, stream []byte) {
+	defer response.writer.commit()
+
+	response.writer.Header().Set("Content-Type", contentType)
+	response.writer.WriteHeader(statusCode)
+
+	if response.doesNotRequireContent(statusCode) {
 		return
 	}
+
+	if stream == nil {
+		return
+	}
+
+	response.writer.Write(stream)
 }
 
 func (response *HttpResponse) doesNotRequireContent(statusCode int) bool {
@@ -196,31 +352,33 @@ func (response *HttpResponse) in(items, item interface{}) bool {
 	return false
 }
 
-func (response *HttpResponse) getMessage(err error) []byte {
-	if err == nil {
-		return nil
-	}
-
-	if value, ok := err.(ErrorFormatter); ok {
-
-		data := map[string]interface{}{"code": value.Code(), "message": value.Error()}
-		if value.Description() != nil {
-			data["description"] = value.Description()
-		}
-
-		if value.InfoURL() != nil {
-			data["info_url"] = value.InfoURL()
-		}
+// errorMessage the default format of the error responses
+type errorMessage struct {
+	XMLName     xml.Name      `json:"-" xml:"error"`
+	Code        int           `json:"code" xml:"code"`
+	Message     string        `json:"message" xml:"message"`
+	Description *string       `json:"description,omitempty" xml:"description,omitempty"`
+	InfoURL     *string       `json:"info_url,omitempty" xml:"info_url,omitempty"`
+	ErrorID     string        `json:"error_id,omitempty" xml:"error_id,omitempty"`
+	Errors      fieldMessages `json:"errors,omitempty" xml:"errors,omitempty"`
+}
 
-		response, err := json.Marshal(data)
-		if err != nil {
-			return []byte(err.Error())
-		}
+func newErrorMessage(err ErrorFormatter) *errorMessage {
+	message := &errorMessage{
+		Code:        err.Code(),
+		Message:     err.Error(),
+		Description: err.Description(),
+		InfoURL:     err.InfoURL(),
+	}
 
-		return response
+	switch value := err.(type) {
+	case *internalError:
+		message.ErrorID = value.id
+	case *ValidationErrors:
+		message.Errors = value.fieldMessages()
 	}
 
-	return []byte(err.Error())
+	return message
 }
 
 func (response *HttpResponse) registerAttributes() {
@@ -251,28 +409,23 @@ func (response *HttpResponse) registerAttributes() {
 // 	return buf.Bytes(), nil
 // }
 
-func (response *HttpResponse) composeCustomError(err ErrorFormatter) {
-	switch err.Status() {
-	case http.StatusUnauthorized:
-		response.Unauthorized(err)
-	case http.StatusForbidden:
-		response.Forbidden(err)
-	case http.StatusConflict:
-		response.Conflict(err)
-	case http.StatusUnprocessableEntity:
-		response.UnprocessableEntity(err)
-	case http.StatusNotFound:
-		response.NotFound(err)
-	case http.StatusBadRequest:
-		response.BadRequest(err)
-	case http.StatusInternalServerError:
-		break
-	default:
-		response.InternalServerError(err)
+// errorStatus the status the error is rendered with, the ones that
+// aren't an error status are rendered as http.StatusInternalServerError
+func errorStatus(err ErrorFormatter) int {
+	status := err.Status()
+	if status < http.StatusBadRequest || status > 599 {
+		return http.StatusInternalServerError
 	}
+
+	return status
 }
 
 func (response *HttpResponse) buildLocationURL(r *http.Request, resource interface{}) string {
+	if r == nil {
+		// without a request the location is relative to the current one
+		return fmt.Sprintf("%v", resource)
+	}
+
 	protocol := "http"
 	if r.TLS != nil {
 		protocol = "https"
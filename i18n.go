@@ -0,0 +1,255 @@
+package responder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultLanguage the language used when the request doesn't state any
+const defaultLanguage = "en"
+
+// Translation the localized message and description of an error code
+type Translation struct {
+	Message     string `json:"message"`
+	Description string `json:"description,omitempty"`
+}
+
+// Translator resolves the localized message and description of the error codes
+type Translator interface {
+	// Translate returns the translation of the code on the language,
+	// false when there isn't one
+	Translate(language string, code int) (Translation, bool)
+	// Languages the language tags with translations, they are
+	// negotiated with the Accept-Language header
+	Languages() []string
+}
+
+// WithTranslator sets the translator localizing the error responses,
+// the message and description of the ErrorFormatter are kept for the
+// codes it doesn't translate
+func WithTranslator(translator Translator) Option {
+	return func(config *Config) {
+		config.Translator = translator
+	}
+}
+
+// WithLanguage sets the language used when none of the languages
+// accepted by the request is translated
+func WithLanguage(language string) Option {
+	return func(config *Config) {
+		config.Language = normalizeLanguage(language)
+	}
+}
+
+// translate the translation of the error for the request, with the language
+// it is written in. The response varies on Accept-Language when translated
+func (response *HttpResponse) translate(err ErrorFormatter) (Translation, string, bool) {
+	translator := response.config.Translator
+	if translator == nil {
+		return Translation{}, "", false
+	}
+
+	// the shared caches must not serve a language to every client
+	vary(response.writer.Header(), "Accept-Language")
+	if err == nil {
+		return Translation{}, "", false
+	}
+
+	header := ""
+	if response.config.request != nil {
+		header = response.config.request.Header.Get("Accept-Language")
+	}
+
+	for _, language := range languageChain(header, translator.Languages(), response.config.Language) {
+		if translation, ok := translator.Translate(language, err.Code()); ok {
+			return translation, language, true
+		}
+	}
+
+	return Translation{}, "", false
+}
+
+// languageChain the supported languages to try in order: the ones accepted
+// by the header from the most to the least preferred, matching their
+// primary language as well, and the fallback language at last
+func languageChain(header string, supported []string, fallback string) []string {
+	available := make(map[string]string, len(supported))
+	for _, language := range supported {
+		available[normalizeLanguage(language)] = language
+	}
+
+	chain := make([]string, 0, len(supported)+1)
+	seen := make(map[string]bool)
+	add := func(language string) {
+		if original, ok := available[language]; ok && !seen[language] {
+			seen[language] = true
+			chain = append(chain, original)
+		}
+	}
+
+	for _, language := range parseAcceptLanguage(header) {
+		if language == "*" {
+			add(fallback)
+			continue
+		}
+
+		// es-MX falls back to es
+		for candidate := language; candidate != ""; {
+			add(candidate)
+
+			dash := strings.LastIndexByte(candidate, '-')
+			if dash < 0 {
+				break
+			}
+			candidate = candidate[:dash]
+		}
+	}
+
+	if fallback == "" {
+		fallback = defaultLanguage
+	}
+	add(fallback)
+
+	return chain
+}
+
+// parseAcceptLanguage the language ranges of the header from the most to the
+// least preferred, the ones refused with a zero quality are left out
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		language string
+		quality  float64
+	}
+
+	ranges := make([]weighted, 0)
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		language := normalizeLanguage(params[0])
+		if language == "" {
+			continue
+		}
+
+		item := weighted{language: language, quality: 1}
+		for _, param := range params[1:] {
+			if name, value := splitParam(param); name == "q" {
+				quality, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					quality = 0
+				}
+				item.quality = quality
+			}
+		}
+
+		if item.quality > 0 {
+			ranges = append(ranges, item)
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	languages := make([]string, len(ranges))
+	for i, item := range ranges {
+		languages[i] = item.language
+	}
+
+	return languages
+}
+
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
+
+// Catalog an in-memory Translator
+type Catalog struct {
+	languages    []string
+	translations map[string]map[int]Translation
+}
+
+// NewCatalog returns an empty Catalog
+func NewCatalog() *Catalog {
+	return &Catalog{translations: make(map[string]map[int]Translation)}
+}
+
+// LoadCatalog loads the JSON files of the file system matching the pattern,
+// every file is named after its language, like locales/es-MX.json, and maps
+// the error codes to their translation:
+//
+//	{"40401": {"message": "recurso no encontrado", "description": "..."}}
+func LoadCatalog(fsys fs.FS, pattern string) (*Catalog, error) {
+	files, err := fs.Glob(fsys, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	catalog := NewCatalog()
+	for _, name := range files {
+		file, err := fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+
+		language := strings.TrimSuffix(path.Base(name), path.Ext(name))
+		err = catalog.Load(language, file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("responder: loading %s: %w", name, err)
+		}
+	}
+
+	return catalog, nil
+}
+
+// Add sets the translation of the code on the language
+func (catalog *Catalog) Add(language string, code int, translation Translation) *Catalog {
+	language = normalizeLanguage(language)
+
+	translations, ok := catalog.translations[language]
+	if !ok {
+		translations = make(map[int]Translation)
+		catalog.translations[language] = translations
+		catalog.languages = append(catalog.languages, language)
+	}
+
+	translations[code] = translation
+	return catalog
+}
+
+// Load adds the translations of the language read from the JSON document
+func (catalog *Catalog) Load(language string, r io.Reader) error {
+	var document map[string]Translation
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return err
+	}
+
+	for key, translation := range document {
+		code, err := strconv.Atoi(key)
+		if err != nil {
+			return fmt.Errorf("invalid error code %q", key)
+		}
+
+		catalog.Add(language, code, translation)
+	}
+
+	return nil
+}
+
+// Translate returns the translation of the code on the language
+func (catalog *Catalog) Translate(language string, code int) (Translation, bool) {
+	translation, ok := catalog.translations[normalizeLanguage(language)][code]
+	return translation, ok
+}
+
+// Languages the languages with translations
+func (catalog *Catalog) Languages() []string {
+	languages := make([]string, len(catalog.languages))
+	copy(languages, catalog.languages)
+	return languages
+}
@@ -0,0 +1,163 @@
+package responder_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/martin3zra/responder"
+)
+
+func newCatalog() *responder.Catalog {
+	return responder.NewCatalog().
+		Add("en", 5, responder.Translation{Message: "resource not found"}).
+		Add("es", 5, responder.Translation{Message: "recurso no encontrado", Description: "el recurso no existe"}).
+		Add("es-MX", 5, responder.Translation{Message: "no encontramos el recurso"}).
+		Add("fr", 3, responder.Translation{Message: "requête invalide"})
+}
+
+func TestTranslatedErrors(t *testing.T) {
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		language       string
+		message        string
+		description    string
+	}{
+		{"it picks the exact language", "es-MX", "es-mx", "no encontramos el recurso", "resource not found description"},
+		{"it falls back to the primary language", "es-AR", "es", "recurso no encontrado", "el recurso no existe"},
+		{"it honors the quality values", "fr;q=0.9, es;q=0.8, en;q=0.1", "es", "recurso no encontrado", "el recurso no existe"},
+		{"it skips the refused languages", "es;q=0, de", "en", "resource not found", "resource not found description"},
+		{"it falls back to the default language", "", "en", "resource not found", "resource not found description"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := buildRequest(t)
+			if test.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", test.acceptLanguage)
+			}
+
+			respond := responder.New(rr, responder.WithTranslator(newCatalog()), responder.WithRequest(req))
+			respond.Error(new(notFound))
+
+			assertNotFound(t, rr)
+			assertVaryLanguage(t, rr)
+			if language := rr.Header().Get("Content-Language"); language != test.language {
+				t.Errorf("handler returned wrong language: got %q want %q", language, test.language)
+			}
+
+			responseMap := transform(t, rr)
+			if responseMap["message"] != test.message {
+				t.Errorf("handler returned wrong message: got %v want %v", responseMap["message"], test.message)
+			}
+
+			if responseMap["description"] != test.description {
+				t.Errorf("handler returned wrong description: got %v want %v", responseMap["description"], test.description)
+			}
+		})
+	}
+
+	t.Run("it keeps the message of the codes without translation", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := buildRequest(t)
+		req.Header.Set("Accept-Language", "es")
+
+		respond := responder.New(rr, responder.WithTranslator(newCatalog()), responder.WithRequest(req))
+		respond.Error(new(badRequest))
+
+		assertBadRequest(t, rr)
+		assertVaryLanguage(t, rr)
+		if language := rr.Header().Get("Content-Language"); language != "" {
+			t.Errorf("handler returned unexpected language: got %q", language)
+		}
+
+		if message := transform(t, rr)["message"]; message != "bad Request" {
+			t.Errorf("handler returned wrong message: got %v want bad Request", message)
+		}
+	})
+
+	t.Run("it uses the configured fallback language", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithTranslator(newCatalog()), responder.WithLanguage("fr"))
+		respond.Error(new(badRequest))
+
+		if message := transform(t, rr)["message"]; message != "requête invalide" {
+			t.Errorf("handler returned wrong message: got %v want requête invalide", message)
+		}
+	})
+
+	t.Run("it translates the problem details", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := buildRequest(t)
+		req.Header.Set("Accept-Language", "es")
+
+		respond := responder.New(rr,
+			responder.WithTranslator(newCatalog()),
+			responder.WithErrorFormat(responder.FormatProblem),
+			responder.WithRequest(req),
+		)
+		respond.Error(new(notFound))
+
+		responseMap := transform(t, rr)
+		if responseMap["title"] != "recurso no encontrado" {
+			t.Errorf("handler returned wrong title: got %v want recurso no encontrado", responseMap["title"])
+		}
+
+		if responseMap["detail"] != "el recurso no existe" {
+			t.Errorf("handler returned wrong detail: got %v want el recurso no existe", responseMap["detail"])
+		}
+	})
+}
+
+func assertVaryLanguage(t *testing.T, w http.ResponseWriter) {
+	for _, value := range w.Header().Values("Vary") {
+		if value == "Accept-Language" {
+			return
+		}
+	}
+
+	t.Errorf("handler returned wrong vary: got %v want Accept-Language", w.Header().Values("Vary"))
+}
+
+func TestLoadCatalog(t *testing.T) {
+
+	t.Run("it loads a language per file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/es.json":    {Data: []byte(`{"5": {"message": "recurso no encontrado"}}`)},
+			"locales/pt_BR.json": {Data: []byte(`{"5": {"message": "recurso não encontrado", "description": "o recurso não existe"}}`)},
+		}
+
+		catalog, err := responder.LoadCatalog(fsys, "locales/*.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if translation, ok := catalog.Translate("es", 5); !ok || translation.Message != "recurso no encontrado" {
+			t.Errorf("catalog returned wrong translation: got %v", translation)
+		}
+
+		if translation, ok := catalog.Translate("pt-BR", 5); !ok || translation.Description != "o recurso não existe" {
+			t.Errorf("catalog returned wrong translation: got %v", translation)
+		}
+
+		if len(catalog.Languages()) != 2 {
+			t.Errorf("catalog returned wrong languages: got %v", catalog.Languages())
+		}
+	})
+
+	t.Run("it rejects the keys that aren't codes", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"es.json": {Data: []byte(`{"not_found": {"message": "recurso no encontrado"}}`)},
+		}
+
+		_, err := responder.LoadCatalog(fsys, "*.json")
+		if err == nil || !strings.Contains(err.Error(), "es.json") {
+			t.Errorf("catalog returned wrong error: got %v", err)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package responder
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// ErrorLog describes an unexpected server error hidden from the client
+type ErrorLog struct {
+	// ID the identifier sent to the client to correlate the error
+	ID string
+	// Status the HTTP status code sent to the client
+	Status int
+	// Err the error that was hidden
+	Err error
+	// Stack the stack trace of the goroutine rendering the error, or
+	// the one of the panic the error comes from
+	Stack []byte
+	// Request the request being responded, nil when it isn't known
+	Request *http.Request
+}
+
+// ErrorLogger receives the unexpected server errors hidden from the client
+type ErrorLogger func(entry ErrorLog)
+
+// WithErrorLogger sets the hook receiving the unexpected server errors,
+// they are written to the standard logger by default
+func WithErrorLogger(logger ErrorLogger) Option {
+	return func(config *Config) {
+		config.ErrorLogger = logger
+	}
+}
+
+// WithDebug renders the details of the unexpected server errors to the
+// client, it is meant for local development only
+func WithDebug(debug bool) Option {
+	return func(config *Config) {
+		config.Debug = debug
+	}
+}
+
+func logError(entry ErrorLog) {
+	log.Printf("responder: error %s (%d): %v", entry.ID, entry.Status, entry.Err)
+}
+
+// hide logs the error and replaces it with one only exposing its ID,
+// the details are kept when debugging
+func (response *HttpResponse) hide(statusCode int, err error) *internalError {
+	internal := &internalError{status: statusCode, id: newErrorID(), err: err, debug: response.config.Debug}
+
+	if logger := response.config.ErrorLogger; logger != nil {
+		stack := debug.Stack()
+
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			stack = panicErr.Stack
+		}
+
+		logger(ErrorLog{
+			ID:      internal.id,
+			Status:  statusCode,
+			Err:     err,
+			Stack:   stack,
+			Request: response.config.request,
+		})
+	}
+
+	return internal
+}
+
+// formatter the ErrorFormatter rendering the error, the details of the
+// unexpected server errors are hidden unless debugging
+func (response *HttpResponse) formatter(statusCode int, err error) ErrorFormatter {
+	if value, ok := response.resolve(err); ok {
+		return value
+	}
+
+	if statusCode >= http.StatusInternalServerError {
+		return response.hide(statusCode, err)
+	}
+
+	return &plainError{status: statusCode, err: err}
+}
+
+func newErrorID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// internalError an unexpected server error, only its ID reaches the client
+type internalError struct {
+	status int
+	id     string
+	err    error
+	debug  bool
+}
+
+func (err *internalError) Status() int { return err.status }
+
+func (err *internalError) Code() int { return err.status }
+
+func (err *internalError) Error() string { return http.StatusText(err.status) }
+
+func (err *internalError) Description() *string {
+	if !err.debug {
+		return nil
+	}
+
+	description := err.err.Error()
+	return &description
+}
+
+func (err *internalError) InfoURL() *string { return nil }
+
+func (err *internalError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"error_id": err.id}
+}
+
+// plainError an error that doesn't implement ErrorFormatter, its
+// message is rendered as the description
+type plainError struct {
+	status int
+	err    error
+}
+
+func (err *plainError) Status() int { return err.status }
+
+func (err *plainError) Code() int { return err.status }
+
+func (err *plainError) Error() string { return http.StatusText(err.status) }
+
+func (err *plainError) Description() *string {
+	description := err.err.Error()
+	return &description
+}
+
+func (err *plainError) InfoURL() *string { return nil }
@@ -0,0 +1,110 @@
+package responder_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestInternalErrors(t *testing.T) {
+	leak := errors.New("pq: relation \"customers\" does not exist")
+
+	t.Run("it hides the details of unexpected errors", func(t *testing.T) {
+		var entries []responder.ErrorLog
+		rr := newStrictRecorder(t)
+		req := buildRequest(t)
+		respond := responder.New(rr, responder.WithRequest(req), responder.WithErrorLogger(func(entry responder.ErrorLog) {
+			entries = append(entries, entry)
+		}))
+		respond.Error(leak)
+
+		assertInternalError(t, rr.ResponseRecorder)
+		assertIsJSON(t, rr)
+		if strings.Contains(rr.Body.String(), "customers") {
+			t.Errorf("handler leaked the error: got %v", rr.Body.String())
+		}
+
+		responseMap := transform(t, rr.ResponseRecorder)
+		if responseMap["message"] != http.StatusText(http.StatusInternalServerError) {
+			t.Errorf("handler returned wrong message: got %v", responseMap["message"])
+		}
+
+		if len(entries) != 1 {
+			t.Fatalf("expected one logged error: got %d", len(entries))
+		}
+
+		entry := entries[0]
+		if entry.ID == "" || responseMap["error_id"] != entry.ID {
+			t.Errorf("handler returned wrong error id: got %v want %v", responseMap["error_id"], entry.ID)
+		}
+
+		if entry.Err != leak || entry.Request != req || entry.Status != http.StatusInternalServerError || len(entry.Stack) == 0 {
+			t.Errorf("logger received wrong entry: got %+v", entry)
+		}
+	})
+
+	t.Run("it keeps the details when debugging", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		respond := responder.New(rr, responder.WithDebug(true), responder.WithErrorLogger(nil))
+		respond.InternalServerError(leak)
+
+		responseMap := transform(t, rr.ResponseRecorder)
+		if responseMap["description"] != leak.Error() {
+			t.Errorf("handler returned wrong description: got %v want %v", responseMap["description"], leak.Error())
+		}
+
+		if _, ok := responseMap["error_id"]; !ok {
+			t.Errorf("expected key `error_id`: got %v", responseMap)
+		}
+	})
+
+	t.Run("it hides the details of problem details", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		respond := responder.New(rr, responder.WithErrorFormat(responder.FormatProblem), responder.WithErrorLogger(nil))
+		respond.Error(leak)
+
+		assertInternalError(t, rr.ResponseRecorder)
+		assertIsProblem(t, rr)
+
+		responseMap := transform(t, rr.ResponseRecorder)
+		if _, ok := responseMap["detail"]; ok {
+			t.Errorf("handler leaked the error: got %v", responseMap["detail"])
+		}
+
+		if _, ok := responseMap["error_id"]; !ok {
+			t.Errorf("expected key `error_id`: got %v", responseMap)
+		}
+	})
+
+	t.Run("it hides the encoding errors", func(t *testing.T) {
+		logged := 0
+		rr := newStrictRecorder(t)
+		respond := responder.New(rr, responder.WithErrorLogger(func(responder.ErrorLog) {
+			logged++
+		}))
+		respond.OK(func() {})
+
+		assertInternalError(t, rr.ResponseRecorder)
+		assertIsJSON(t, rr)
+		transform(t, rr.ResponseRecorder)
+
+		if logged != 1 {
+			t.Errorf("expected the encoding error to be logged: got %d", logged)
+		}
+	})
+
+	t.Run("it renders the client errors as valid JSON", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		responder.New(rr).BadRequest(errors.New("invalid payload"))
+
+		assertBadRequest(t, rr.ResponseRecorder)
+
+		responseMap := transform(t, rr.ResponseRecorder)
+		if responseMap["message"] != http.StatusText(http.StatusBadRequest) || responseMap["description"] != "invalid payload" {
+			t.Errorf("handler returned wrong error: got %v", responseMap)
+		}
+	})
+}
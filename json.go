@@ -0,0 +1,116 @@
+package responder
+
+import (
+	"encoding"
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// JSONEncoder the encoding/json based Encoder, its zero value behaves
+// like json.Marshal
+type JSONEncoder struct {
+	// DisableHTMLEscape keeps <, > and & as they are inside the strings
+	DisableHTMLEscape bool
+	// Prefix and Indent format the output like json.MarshalIndent
+	Prefix string
+	Indent string
+}
+
+// Encode writes the JSON encoding of v into w, the slices that aren't
+// indented are written element by element so they're streamed instead
+// of being held on memory at once
+func (encoder JSONEncoder) Encode(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(trimNewline{w})
+	enc.SetEscapeHTML(!encoder.DisableHTMLEscape)
+	enc.SetIndent(encoder.Prefix, encoder.Indent)
+
+	if encoder.Prefix != "" || encoder.Indent != "" {
+		return enc.Encode(v)
+	}
+
+	items, ok := streamable(v)
+	if !ok {
+		return enc.Encode(v)
+	}
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for i := 0; i < items.Len(); i++ {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		// the elements of a slice are addressable for encoding/json too,
+		// so their pointer receiver marshalers are honored
+		item := items.Index(i)
+		if item.CanAddr() {
+			item = item.Addr()
+		}
+
+		if err := enc.Encode(item.Interface()); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]")
+	return err
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// streamable reports whether v is encoded as a JSON array by encoding/json
+func streamable(v interface{}) (reflect.Value, bool) {
+	items := reflect.ValueOf(v)
+
+	switch items.Kind() {
+	case reflect.Slice:
+		if items.IsNil() {
+			return items, false
+		}
+	case reflect.Array:
+	default:
+		return items, false
+	}
+
+	// byte slices are encoded as base64 strings
+	if items.Type().Elem().Kind() == reflect.Uint8 {
+		return items, false
+	}
+
+	for _, t := range []reflect.Type{items.Type(), reflect.PtrTo(items.Type())} {
+		if t.Implements(marshalerType) || t.Implements(textMarshalerType) {
+			return items, false
+		}
+	}
+
+	return items, true
+}
+
+// MarshalFunc allow you use a marshal function, like the ones offered by
+// the drop-in replacements of encoding/json, as an Encoder
+type MarshalFunc func(v interface{}) ([]byte, error)
+
+// Encode writes the result of f(v) into w
+func (f MarshalFunc) Encode(w io.Writer, v interface{}) error {
+	res, err := f(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(res)
+	return err
+}
+
+// WithJSONEncoder replaces the encoder used for application/json,
+// the default one is the zero value of JSONEncoder
+func WithJSONEncoder(encoder Encoder) Option {
+	return WithEncoder("application/json", encoder)
+}
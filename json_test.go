@@ -0,0 +1,161 @@
+package responder_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	payload := map[string]interface{}{"customer": "<Henry & Co>"}
+
+	cases := []struct {
+		encoder    responder.Encoder
+		expectBody string
+		name       string
+	}{
+		{
+			encoder:    responder.JSONEncoder{},
+			expectBody: `{"customer":"\u003cHenry \u0026 Co\u003e"}`,
+			name:       "it behaves like json.Marshal by default",
+		},
+		{
+			encoder:    responder.JSONEncoder{DisableHTMLEscape: true},
+			expectBody: `{"customer":"<Henry & Co>"}`,
+			name:       "it keeps the html characters",
+		},
+		{
+			encoder:    responder.JSONEncoder{DisableHTMLEscape: true, Indent: "  "},
+			expectBody: "{\n  \"customer\": \"<Henry & Co>\"\n}",
+			name:       "it indents the output",
+		},
+		{
+			encoder: responder.MarshalFunc(func(v interface{}) ([]byte, error) {
+				return []byte(`{"customized":true}`), nil
+			}),
+			expectBody: `{"customized":true}`,
+			name:       "it uses a marshal function",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			responder.New(rr, responder.WithJSONEncoder(item.encoder)).OK(payload)
+
+			assertOK(t, rr)
+			assertIsJSON(t, rr)
+			if rr.Body.String() != item.expectBody {
+				t.Errorf("handler returned wrong body: got %v want %v", rr.Body.String(), item.expectBody)
+			}
+		})
+	}
+
+	t.Run("it uses the package-level encoder", func(t *testing.T) {
+		responder.Configure(responder.WithJSONEncoder(responder.JSONEncoder{Indent: "\t"}))
+		defer responder.Configure(responder.WithJSONEncoder(responder.JSONEncoder{}))
+
+		rr := httptest.NewRecorder()
+		responder.New(rr).OK([]int{1})
+
+		if rr.Body.String() != "[\n\t1\n]" {
+			t.Errorf("handler returned wrong body: got %q", rr.Body.String())
+		}
+	})
+}
+
+func BenchmarkJSONEncoders(b *testing.B) {
+	payload := benchmarkPayload()
+
+	encoders := []struct {
+		encoder responder.Encoder
+		name    string
+	}{
+		{encoder: responder.JSONEncoder{}, name: "JSONEncoder"},
+		{encoder: responder.JSONEncoder{DisableHTMLEscape: true}, name: "JSONEncoder without html escape"},
+		{encoder: responder.JSONEncoder{Indent: "  "}, name: "JSONEncoder indented"},
+		{encoder: responder.MarshalFunc(json.Marshal), name: "json.Marshal"},
+	}
+
+	for _, item := range encoders {
+		b.Run(item.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := item.encoder.Encode(ioutil.Discard, payload); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkOK(b *testing.B) {
+	payload := benchmarkPayload()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		responder.New(httptest.NewRecorder()).OK(payload)
+	}
+}
+
+func benchmarkPayload() []map[string]interface{} {
+	payload := make([]map[string]interface{}, 1000)
+	for i := range payload {
+		payload[i] = map[string]interface{}{
+			"id":       i,
+			"customer": "Henry & Co",
+			"tags":     []string{"<vip>", "wholesale"},
+			"balance":  float64(i) * 1.5,
+		}
+	}
+
+	return payload
+}
+
+// pointerMarshaler implements the marshalers on its pointer
+type pointerMarshaler struct {
+	A int
+}
+
+func (*pointerMarshaler) MarshalJSON() ([]byte, error) {
+	return []byte(`"custom"`), nil
+}
+
+// pointerTextMarshaler implements encoding.TextMarshaler on its pointer
+type pointerTextMarshaler struct {
+	A int
+}
+
+func (*pointerTextMarshaler) MarshalText() ([]byte, error) {
+	return []byte("text"), nil
+}
+
+func TestJSONEncoderPointerMarshalers(t *testing.T) {
+	payloads := []struct {
+		name    string
+		payload interface{}
+	}{
+		{"it honors the pointer receiver MarshalJSON of the elements", []pointerMarshaler{{A: 1}, {A: 2}}},
+		{"it honors the pointer receiver MarshalText of the elements", []pointerTextMarshaler{{A: 1}, {A: 2}}},
+		{"it encodes the arrays like json.Marshal", [2]pointerMarshaler{{A: 1}, {A: 2}}},
+	}
+
+	for _, item := range payloads {
+		t.Run(item.name, func(t *testing.T) {
+			expected, err := json.Marshal(item.payload)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rr := httptest.NewRecorder()
+			responder.New(rr).OK(item.payload)
+
+			if rr.Body.String() != string(expected) {
+				t.Errorf("handler returned wrong body: got %v want %v", rr.Body.String(), string(expected))
+			}
+		})
+	}
+}
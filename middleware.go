@@ -0,0 +1,34 @@
+package responder
+
+import (
+	"context"
+	"net/http"
+)
+
+// respondKey the context key the Middleware stores the Respond with
+type respondKey struct{}
+
+// Middleware builds a Respond configured with the options for every
+// request, the handlers get it back with From. The panics of the
+// handlers are rendered like Recover does
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tracked := newRecoveryWriter(w)
+			respond := NewWithRequest(tracked, r, opts...)
+			r = r.WithContext(context.WithValue(r.Context(), respondKey{}, respond))
+			respond.response.config.request = r
+
+			defer recoverWith(tracked, func() *Respond { return respond })
+
+			next.ServeHTTP(tracked, r)
+		})
+	}
+}
+
+// From returns the Respond the Middleware built for the request,
+// nil when the request didn't go through it
+func From(r *http.Request) *Respond {
+	respond, _ := r.Context().Value(respondKey{}).(*Respond)
+	return respond
+}
@@ -0,0 +1,85 @@
+package responder_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+	"github.com/martin3zra/responder/respondertest"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	t.Run("it injects a configured Respond", func(t *testing.T) {
+		handler := responder.Middleware(responder.WithEnvelope(responder.DefaultEnvelope()))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				respond := responder.From(r)
+				if respond.Request() != r {
+					t.Errorf("respond returned wrong request: got %v want %v", respond.Request(), r)
+				}
+
+				respond.OK(customer{Name: "Henry"})
+			}),
+		)
+
+		rr := httptest.NewRecorder()
+		req := buildRequest(t)
+		req.Header.Set("Accept", "application/json")
+		handler.ServeHTTP(rr, req)
+
+		assertOK(t, rr)
+		if _, ok := transform(t, rr)["data"]; !ok {
+			t.Errorf("handler returned a body without envelope: got %s", rr.Body.String())
+		}
+	})
+
+	t.Run("it keeps the headers set by the handler", func(t *testing.T) {
+		handler := responder.Middleware()(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "no-store")
+				responder.From(r).OK(customer{Name: "Henry"})
+			}),
+		)
+
+		rr := newStrictRecorder(t)
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertOK(t, rr.ResponseRecorder)
+		if cache := rr.Header().Get("Cache-Control"); cache != "no-store" {
+			t.Errorf("handler returned wrong cache control: got %q want %q", cache, "no-store")
+		}
+	})
+
+	t.Run("it returns nil without the middleware", func(t *testing.T) {
+		if respond := responder.From(buildRequest(t)); respond != nil {
+			t.Errorf("From returned unexpected respond: got %v", respond)
+		}
+	})
+
+	t.Run("it renders the panics as server errors", func(t *testing.T) {
+		reporter := respondertest.NewReporter()
+		cause := errors.New("nil map")
+		handler := responder.Middleware(responder.WithReporter(reporter), responder.WithErrorLogger(func(responder.ErrorLog) {}))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				panic(cause)
+			}),
+		)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertInternalError(t, rr)
+		assertIsJSON(t, rr)
+
+		report, ok := reporter.Last()
+		if !ok {
+			t.Fatal("reporter didn't receive the error")
+		}
+
+		if !errors.Is(report.Err, cause) {
+			t.Errorf("reporter received wrong error: got %v want %v", report.Err, cause)
+		}
+	})
+}
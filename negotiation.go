@@ -0,0 +1,148 @@
+package responder
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// acceptRange a media range of the Accept header with its quality
+type acceptRange struct {
+	mediaType string
+	subtype   string
+	quality   float64
+}
+
+// parseAccept parses the media ranges of an Accept header,
+// the ranges with an invalid quality are ignored
+func parseAccept(header string) []acceptRange {
+	ranges := make([]acceptRange, 0)
+
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaRange == "" {
+			continue
+		}
+
+		if mediaRange == "*" {
+			mediaRange = "*/*"
+		}
+
+		slash := strings.IndexByte(mediaRange, '/')
+		if slash < 0 {
+			continue
+		}
+
+		item := acceptRange{mediaType: mediaRange[:slash], subtype: mediaRange[slash+1:], quality: 1}
+		valid := true
+		for _, param := range params[1:] {
+			name, value := splitParam(param)
+			if name != "q" {
+				continue
+			}
+
+			quality, err := strconv.ParseFloat(value, 64)
+			if err != nil || quality < 0 || quality > 1 {
+				valid = false
+				break
+			}
+
+			item.quality = quality
+		}
+
+		if valid {
+			ranges = append(ranges, item)
+		}
+	}
+
+	return ranges
+}
+
+// vary adds the request header the response depends on to the Vary header
+func vary(header http.Header, name string) {
+	for _, value := range header.Values("Vary") {
+		for _, item := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(item), name) {
+				return
+			}
+		}
+	}
+
+	header.Add("Vary", name)
+}
+
+func splitParam(param string) (string, string) {
+	pair := strings.SplitN(param, "=", 2)
+	name := strings.ToLower(strings.TrimSpace(pair[0]))
+	if len(pair) == 1 {
+		return name, ""
+	}
+
+	return name, strings.Trim(strings.TrimSpace(pair[1]), `"`)
+}
+
+// precedence how specific a media range is matching the given media type,
+// -1 when it doesn't match at all
+func (item acceptRange) precedence(mediaType string) int {
+	slash := strings.IndexByte(mediaType, '/')
+	if slash < 0 {
+		return -1
+	}
+
+	mainType, subtype := mediaType[:slash], mediaType[slash+1:]
+	switch {
+	case item.mediaType == mainType && item.subtype == subtype:
+		return 2
+	case item.mediaType == mainType && item.subtype == "*":
+		return 1
+	case item.mediaType == "*" && item.subtype == "*":
+		return 0
+	}
+
+	return -1
+}
+
+// quality the quality the client gives to the media type, taken from
+// the most specific range matching it
+func quality(ranges []acceptRange, mediaType string) float64 {
+	best, value := -1, 0.0
+	for _, item := range ranges {
+		if precedence := item.precedence(mediaType); precedence > best {
+			best, value = precedence, item.quality
+		}
+	}
+
+	return value
+}
+
+// negotiate picks the offered media type the Accept header prefers,
+// the ties are resolved by the order of the offers. It returns false
+// when none of them is acceptable
+func negotiate(header string, offers []string) (string, bool) {
+	if len(offers) == 0 {
+		return "", false
+	}
+
+	if strings.TrimSpace(header) == "" {
+		return offers[0], true
+	}
+
+	ranges := parseAccept(header)
+	if len(ranges) == 0 {
+		return offers[0], true
+	}
+
+	candidates := make([]string, len(offers))
+	copy(candidates, offers)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return quality(ranges, candidates[i]) > quality(ranges, candidates[j])
+	})
+
+	if quality(ranges, candidates[0]) <= 0 {
+		return "", false
+	}
+
+	return candidates[0], true
+}
@@ -0,0 +1,223 @@
+package responder_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+// withXML enables the XML encoder, it isn't available by default
+var withXML = responder.WithEncoder("application/xml", responder.XMLEncoder{})
+
+func TestContentNegotiation(t *testing.T) {
+	msgpack := responder.EncoderFunc(func(w io.Writer, v interface{}) error {
+		_, err := fmt.Fprintf(w, "msgpack:%v", v)
+		return err
+	})
+
+	cases := []struct {
+		accept            string
+		expectCode        int
+		expectContentType string
+		name              string
+	}{
+		{
+			accept:            "",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/json",
+			name:              "it returns json when there's no Accept header",
+		},
+		{
+			accept:            "application/xml",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/xml",
+			name:              "it returns xml when it is requested",
+		},
+		{
+			accept:            "application/xml;q=0.5, application/json",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/json",
+			name:              "it honors the quality values",
+		},
+		{
+			accept:            "*/*;q=0.1, application/xml",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/xml",
+			name:              "it prefers the most specific range",
+		},
+		{
+			accept:            "application/*",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/json",
+			name:              "it resolves wildcards by the registration order",
+		},
+		{
+			accept:            "application/x-msgpack",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/x-msgpack",
+			name:              "it uses the registered encoders",
+		},
+		{
+			accept:            "application/json;q=0, */*",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/xml",
+			name:              "it skips the refused media types",
+		},
+		{
+			accept:            "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			expectCode:        http.StatusOK,
+			expectContentType: "application/xml",
+			name:              "it honors the browsers once XML is enabled",
+		},
+		{
+			accept:     "text/html",
+			expectCode: http.StatusNotAcceptable,
+			name:       "it returns http status 406 when nothing is acceptable",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			req := buildRequest(t)
+			req.Header.Set("Accept", item.accept)
+
+			rr := httptest.NewRecorder()
+			respond := responder.New(rr, responder.WithRequest(req), withXML, responder.WithEncoder("application/x-msgpack", msgpack))
+			respond.OK(customer{Name: "Henry"})
+
+			assertStatusCode(t, item.expectCode, rr.Code)
+			if item.expectContentType != "" && rr.Header().Get("Content-Type") != item.expectContentType {
+				t.Errorf("handler returned wrong content type: got %v want %v",
+					rr.Header().Get("Content-Type"), item.expectContentType)
+			}
+		})
+	}
+}
+
+func TestContentNegotiationForErrors(t *testing.T) {
+
+	t.Run("it encodes the error as xml", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Accept", "application/xml")
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithRequest(req), withXML).Error(new(notFound))
+
+		assertNotFound(t, rr)
+
+		var body struct {
+			Code    int    `xml:"code"`
+			Message string `xml:"message"`
+		}
+		if err := xml.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Cannot convert to xml: %v", err)
+		}
+
+		if body.Code != 5 || body.Message != "resource not found" {
+			t.Errorf("handler returned wrong error: got %+v", body)
+		}
+	})
+
+	t.Run("it encodes problem details as xml", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Accept", "application/xml")
+
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithRequest(req), withXML, responder.WithErrorFormat(responder.FormatProblem))
+		respond.Conflict(new(outOfCredit))
+
+		assertConflict(t, rr)
+		if rr.Header().Get("Content-Type") != "application/problem+xml" {
+			t.Errorf("handler returned wrong content type: got %v want %v",
+				rr.Header().Get("Content-Type"), "application/problem+xml")
+		}
+
+		if !strings.Contains(rr.Body.String(), `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>out of credit</title>`) {
+			t.Errorf("handler returned wrong problem: got %v", rr.Body.String())
+		}
+	})
+
+	t.Run("it falls back to the first encoder when nothing is acceptable", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Accept", "text/html")
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithRequest(req)).NotFound(new(notFound))
+
+		assertNotFound(t, rr)
+		assertIsJSON(t, rr)
+	})
+}
+
+type customer struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func TestDefaultEncoders(t *testing.T) {
+	browser := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+	t.Run("it answers the browsers with json", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Accept", browser)
+
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithRequest(req), responder.WithEnvelope(responder.DefaultEnvelope()))
+		respond.OK(map[string]interface{}{"name": "Henry"})
+
+		assertOK(t, rr)
+		assertContentType(t, rr, "application/json")
+	})
+
+	t.Run("it doesn't offer xml unless enabled", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Accept", "application/xml")
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithRequest(req)).OK([]string{"a", "b"})
+
+		assertStatusCode(t, http.StatusNotAcceptable, rr.Code)
+	})
+}
+
+func TestVaryAccept(t *testing.T) {
+	cases := []struct {
+		name    string
+		accept  string
+		respond func(respond *responder.Respond)
+	}{
+		{"it varies the success responses", "application/json", func(respond *responder.Respond) { respond.OK(customer{Name: "Henry"}) }},
+		{"it varies the error responses", "application/json", func(respond *responder.Respond) { respond.NotFound(new(notFound)) }},
+		{"it varies the empty responses", "application/json", func(respond *responder.Respond) { respond.NoContent() }},
+		{"it varies the not acceptable responses", "text/html", func(respond *responder.Respond) { respond.OK(customer{Name: "Henry"}) }},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := buildRequest(t)
+			req.Header.Set("Accept", c.accept)
+
+			rr := httptest.NewRecorder()
+			rr.Header().Set("Vary", "Origin")
+			c.respond(responder.New(rr, responder.WithRequest(req)))
+
+			if vary := rr.Header().Values("Vary"); len(vary) != 2 || vary[1] != "Accept" {
+				t.Errorf("handler returned wrong vary: got %v want [Origin Accept]", vary)
+			}
+		})
+	}
+
+	t.Run("it doesn't vary without the request", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).OK(customer{Name: "Henry"})
+
+		if vary := rr.Header().Get("Vary"); vary != "" {
+			t.Errorf("handler returned unexpected vary: got %q", vary)
+		}
+	})
+}
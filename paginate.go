@@ -0,0 +1,338 @@
+package responder
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TotalUnknown the total of the pages that don't count their items
+const TotalUnknown = -1
+
+// the query parameters of the paginated requests
+const (
+	pageParam    = "page"
+	perPageParam = "per_page"
+	cursorParam  = "cursor"
+)
+
+// Page describes the page of items sent by Paginated. The offset pagination
+// numbers the pages from 1, the cursor pagination leaves Number as zero
+type Page struct {
+	// Number the number of the page, starting at 1
+	Number int
+	// PerPage the size of the pages
+	PerPage int
+	// Total the items of every page, TotalUnknown when they aren't counted.
+	// The cursor pages rarely count them, theirs is only sent with TotalKnown
+	Total int
+	// TotalKnown reports that the Total of a cursor page was counted
+	TotalKnown bool
+	// Cursor the cursor the page was requested with
+	Cursor string
+	// NextCursor the cursor of the next page, empty on the last one
+	NextCursor string
+	// PrevCursor the cursor of the previous page, empty on the first one
+	PrevCursor string
+}
+
+// Paginated responds OK with the page of items in the envelope, or in the data
+// and meta sections when there isn't one. The first, prev, next and last links
+// are sent in the Link header and the total in the X-Total-Count header
+func (response *HttpResponse) Paginated(items interface{}, page Page) {
+	header := response.writer.Header()
+	if total, ok := page.total(); ok {
+		header.Set("X-Total-Count", strconv.Itoa(total))
+	}
+
+	if links := page.links(response.pageURL(), itemsLen(items)); links != "" {
+		header.Set("Link", links)
+	}
+
+	meta := page.meta()
+	for key, value := range response.meta {
+		meta[key] = value
+	}
+
+	envelope := response.config.Envelope
+	if envelope == nil {
+		// the flash messages are sent as cookies, like without envelope
+		response.registerAttributes()
+		envelope = &Envelope{DataKey: "data", MetaKey: "meta"}
+	}
+
+	response.asEnveloped(http.StatusOK, items, envelope, meta)
+}
+
+func (page Page) cursorBased() bool {
+	return page.Number == 0
+}
+
+// total the items of every page, false when they weren't counted
+func (page Page) total() (int, bool) {
+	if page.Total < 0 || (page.cursorBased() && !page.TotalKnown) {
+		return 0, false
+	}
+
+	return page.Total, true
+}
+
+func (page Page) lastNumber() int {
+	if page.Total <= 0 || page.PerPage <= 0 {
+		return 1
+	}
+
+	return (page.Total + page.PerPage - 1) / page.PerPage
+}
+
+func (page Page) meta() map[string]interface{} {
+	meta := map[string]interface{}{perPageParam: page.PerPage}
+	total, counted := page.total()
+	if counted {
+		meta["total"] = total
+	}
+
+	if !page.cursorBased() {
+		meta[pageParam] = page.Number
+		if counted {
+			meta["last_page"] = page.lastNumber()
+		}
+
+		return meta
+	}
+
+	if page.NextCursor != "" {
+		meta["next_cursor"] = page.NextCursor
+	}
+
+	if page.PrevCursor != "" {
+		meta["prev_cursor"] = page.PrevCursor
+	}
+
+	return meta
+}
+
+// links the Link header of the page as described by RFC 8288, count is
+// the items on the page, -1 when they can't be counted
+func (page Page) links(base *url.URL, count int) string {
+	links := make([]string, 0, 4)
+	link := func(rel string, params map[string]string) {
+		target := *base
+		query := target.Query()
+		query.Del(pageParam)
+		query.Del(cursorParam)
+		for name, value := range params {
+			query.Set(name, value)
+		}
+
+		if page.PerPage > 0 {
+			query.Set(perPageParam, strconv.Itoa(page.PerPage))
+		}
+
+		target.RawQuery = query.Encode()
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.String(), rel))
+	}
+
+	if page.cursorBased() {
+		link("first", nil)
+		if page.PrevCursor != "" {
+			link("prev", map[string]string{cursorParam: page.PrevCursor})
+		}
+
+		if page.NextCursor != "" {
+			link("next", map[string]string{cursorParam: page.NextCursor})
+		}
+
+		return strings.Join(links, ", ")
+	}
+
+	number := func(n int) map[string]string {
+		return map[string]string{pageParam: strconv.Itoa(n)}
+	}
+
+	link("first", number(1))
+	if page.Number > 1 {
+		link("prev", number(page.Number-1))
+	}
+
+	if page.Total >= 0 {
+		if page.Number < page.lastNumber() {
+			link("next", number(page.Number+1))
+		}
+
+		link("last", number(page.lastNumber()))
+	} else if count >= 0 && count >= page.PerPage {
+		// a full page may be followed by another one
+		link("next", number(page.Number+1))
+	}
+
+	return strings.Join(links, ", ")
+}
+
+// pageURL the URL of the request being responded, the links are
+// relative to it when it isn't known
+func (response *HttpResponse) pageURL() *url.URL {
+	r := response.config.request
+	if r == nil {
+		return &url.URL{}
+	}
+
+	base := *r.URL
+	if base.Host == "" {
+		base.Host = r.Host
+	}
+
+	if base.Scheme == "" {
+		base.Scheme = "http"
+		if r.TLS != nil {
+			base.Scheme = "https"
+		}
+	}
+
+	return &base
+}
+
+// itemsLen the length of the items, -1 when they aren't a slice or array
+func itemsLen(items interface{}) int {
+	value := reflect.ValueOf(items)
+	if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+		return value.Len()
+	}
+
+	return -1
+}
+
+// PageLimits bounds the pages requested by the clients
+type PageLimits struct {
+	// DefaultPerPage the size of the pages when the request doesn't ask for one
+	DefaultPerPage int
+	// MaxPerPage the largest page size, the larger ones are reduced to it
+	MaxPerPage int
+}
+
+// DefaultPageLimits returns pages of 20 items, up to 100
+func DefaultPageLimits() PageLimits {
+	return PageLimits{DefaultPerPage: 20, MaxPerPage: 100}
+}
+
+// PageRequest the page asked for by the page, per_page and cursor
+// query parameters of a request
+type PageRequest struct {
+	// Number the number of the page, zero when it was asked by cursor
+	Number int
+	// PerPage the size of the page
+	PerPage int
+	// Cursor the cursor of the page, empty for the offset pagination
+	Cursor string
+}
+
+// Offset the items before the page on the offset pagination
+func (request PageRequest) Offset() int {
+	if request.Number < 1 {
+		return 0
+	}
+
+	return (request.Number - 1) * request.PerPage
+}
+
+// Page describes the page of the request holding the total items
+func (request PageRequest) Page(total int) Page {
+	return Page{Number: request.Number, PerPage: request.PerPage, Total: total, TotalKnown: total >= 0, Cursor: request.Cursor}
+}
+
+// ParsePage reads the page asked for by the page and per_page query
+// parameters, the first one by default. The invalid parameters are
+// rejected with a ParamError
+func ParsePage(r *http.Request, limits PageLimits) (PageRequest, error) {
+	request, err := parsePerPage(r, limits)
+	if err != nil {
+		return PageRequest{}, err
+	}
+
+	query := r.URL.Query()
+	if query.Get(cursorParam) != "" {
+		return PageRequest{}, &ParamError{Param: cursorParam, Reason: "isn't supported, use page instead"}
+	}
+
+	request.Number = 1
+	if value := query.Get(pageParam); value != "" {
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 {
+			return PageRequest{}, &ParamError{Param: pageParam, Reason: "must be a positive integer"}
+		}
+
+		request.Number = number
+	}
+
+	return request, nil
+}
+
+// ParseCursor reads the page asked for by the cursor and per_page query
+// parameters, the first one has no cursor. The invalid parameters are
+// rejected with a ParamError
+func ParseCursor(r *http.Request, limits PageLimits) (PageRequest, error) {
+	request, err := parsePerPage(r, limits)
+	if err != nil {
+		return PageRequest{}, err
+	}
+
+	query := r.URL.Query()
+	if query.Get(pageParam) != "" {
+		return PageRequest{}, &ParamError{Param: pageParam, Reason: "isn't supported, use cursor instead"}
+	}
+
+	request.Cursor = query.Get(cursorParam)
+	return request, nil
+}
+
+func parsePerPage(r *http.Request, limits PageLimits) (PageRequest, error) {
+	request := PageRequest{PerPage: limits.DefaultPerPage}
+
+	if value := r.URL.Query().Get(perPageParam); value != "" {
+		perPage, err := strconv.Atoi(value)
+		if err != nil || perPage < 1 {
+			return PageRequest{}, &ParamError{Param: perPageParam, Reason: "must be a positive integer"}
+		}
+
+		request.PerPage = perPage
+	}
+
+	if limits.MaxPerPage > 0 && request.PerPage > limits.MaxPerPage {
+		request.PerPage = limits.MaxPerPage
+	}
+
+	return request, nil
+}
+
+// ParamError describes an invalid query parameter
+type ParamError struct {
+	// Param the name of the query parameter
+	Param string
+	// Reason what is wrong with its value
+	Reason string
+}
+
+// Status http.StatusBadRequest
+func (err *ParamError) Status() int { return http.StatusBadRequest }
+
+// Code http.StatusBadRequest as well
+func (err *ParamError) Code() int { return http.StatusBadRequest }
+
+func (err *ParamError) Error() string { return "invalid query parameter" }
+
+// Description names the parameter and what is wrong with it
+func (err *ParamError) Description() *string {
+	description := fmt.Sprintf("%s %s", err.Param, err.Reason)
+	return &description
+}
+
+// InfoURL ...
+func (err *ParamError) InfoURL() *string { return nil }
+
+// Extensions renders the name of the parameter
+func (err *ParamError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"param": err.Param}
+}
@@ -0,0 +1,212 @@
+package responder_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func buildPageRequest(t *testing.T, target string) *http.Request {
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return req
+}
+
+func TestPaginated(t *testing.T) {
+
+	t.Run("it renders the offset page", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := buildPageRequest(t, "http://localhost/customers?page=2&per_page=2&sort=name")
+
+		respond := responder.NewWithRequest(rr, req)
+		respond.Paginated([]customer{{Name: "Henry"}, {Name: "Jane"}}, responder.Page{Number: 2, PerPage: 2, Total: 5})
+
+		assertOK(t, rr)
+		if total := rr.Header().Get("X-Total-Count"); total != "5" {
+			t.Errorf("handler returned wrong total: got %q want %q", total, "5")
+		}
+
+		expected := `<http://localhost/customers?page=1&per_page=2&sort=name>; rel="first", ` +
+			`<http://localhost/customers?page=1&per_page=2&sort=name>; rel="prev", ` +
+			`<http://localhost/customers?page=3&per_page=2&sort=name>; rel="next", ` +
+			`<http://localhost/customers?page=3&per_page=2&sort=name>; rel="last"`
+		if link := rr.Header().Get("Link"); link != expected {
+			t.Errorf("handler returned wrong links:\ngot  %s\nwant %s", link, expected)
+		}
+
+		responseMap := transform(t, rr)
+		if data, ok := responseMap["data"].([]interface{}); !ok || len(data) != 2 {
+			t.Errorf("handler returned wrong data: got %v", responseMap["data"])
+		}
+
+		meta, _ := responseMap["meta"].(map[string]interface{})
+		expectedMeta := map[string]interface{}{"page": float64(2), "per_page": float64(2), "total": float64(5), "last_page": float64(3)}
+		for key, value := range expectedMeta {
+			if meta[key] != value {
+				t.Errorf("handler returned wrong %s: got %v want %v", key, meta[key], value)
+			}
+		}
+	})
+
+	t.Run("it links the next page of a full page without total", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := buildPageRequest(t, "http://localhost/customers")
+
+		respond := responder.NewWithRequest(rr, req)
+		respond.Paginated([]customer{{Name: "Henry"}}, responder.Page{Number: 1, PerPage: 1, Total: responder.TotalUnknown})
+
+		if total := rr.Header().Get("X-Total-Count"); total != "" {
+			t.Errorf("handler returned unexpected total: got %q", total)
+		}
+
+		expected := `<http://localhost/customers?page=1&per_page=1>; rel="first", ` +
+			`<http://localhost/customers?page=2&per_page=1>; rel="next"`
+		if link := rr.Header().Get("Link"); link != expected {
+			t.Errorf("handler returned wrong links:\ngot  %s\nwant %s", link, expected)
+		}
+	})
+
+	t.Run("it renders the cursor page", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := buildPageRequest(t, "http://localhost/customers?cursor=b&per_page=2")
+
+		respond := responder.NewWithRequest(rr, req)
+		respond.Paginated([]customer{{Name: "Henry"}}, responder.Page{PerPage: 2, Total: responder.TotalUnknown, Cursor: "b", PrevCursor: "a", NextCursor: "c"})
+
+		expected := `<http://localhost/customers?per_page=2>; rel="first", ` +
+			`<http://localhost/customers?cursor=a&per_page=2>; rel="prev", ` +
+			`<http://localhost/customers?cursor=c&per_page=2>; rel="next"`
+		if link := rr.Header().Get("Link"); link != expected {
+			t.Errorf("handler returned wrong links:\ngot  %s\nwant %s", link, expected)
+		}
+
+		meta, _ := transform(t, rr)["meta"].(map[string]interface{})
+		if meta["next_cursor"] != "c" || meta["prev_cursor"] != "a" {
+			t.Errorf("handler returned wrong meta: got %v", meta)
+		}
+	})
+
+	t.Run("it doesn't send the total of a cursor page by default", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).Paginated([]customer{{Name: "Henry"}}, responder.Page{PerPage: 20, NextCursor: "b"})
+
+		if total := rr.Header().Get("X-Total-Count"); total != "" {
+			t.Errorf("handler returned unexpected total: got %q", total)
+		}
+
+		meta, _ := transform(t, rr)["meta"].(map[string]interface{})
+		if _, ok := meta["total"]; ok {
+			t.Errorf("handler returned unexpected total: got %v", meta)
+		}
+	})
+
+	t.Run("it sends the total of a cursor page when known", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).Paginated([]customer{{Name: "Henry"}}, responder.Page{PerPage: 20, NextCursor: "b", Total: 0, TotalKnown: true})
+
+		if total := rr.Header().Get("X-Total-Count"); total != "0" {
+			t.Errorf("handler returned wrong total: got %q want %q", total, "0")
+		}
+	})
+
+	t.Run("it uses the configured envelope", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithEnvelope(responder.Envelope{DataKey: "items", MetaKey: "pagination"}))
+		respond.Paginated([]customer{}, responder.Page{Number: 1, PerPage: 20, Total: 0})
+
+		responseMap := transform(t, rr)
+		if _, ok := responseMap["items"]; !ok {
+			t.Errorf("handler returned wrong body: got %v", responseMap)
+		}
+
+		if _, ok := responseMap["pagination"]; !ok {
+			t.Errorf("handler returned wrong body: got %v", responseMap)
+		}
+
+		if link := rr.Header().Get("Link"); link != `<?page=1&per_page=20>; rel="first", <?page=1&per_page=20>; rel="last"` {
+			t.Errorf("handler returned wrong links: got %s", link)
+		}
+	})
+}
+
+func TestParsePage(t *testing.T) {
+	limits := responder.DefaultPageLimits()
+
+	cases := []struct {
+		name     string
+		query    string
+		expected responder.PageRequest
+	}{
+		{"it defaults to the first page", "", responder.PageRequest{Number: 1, PerPage: 20}},
+		{"it reads the page", "?page=3&per_page=50", responder.PageRequest{Number: 3, PerPage: 50}},
+		{"it limits the page size", "?per_page=500", responder.PageRequest{Number: 1, PerPage: 100}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			request, err := responder.ParsePage(buildPageRequest(t, "http://localhost/customers"+c.query), limits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if request != c.expected {
+				t.Errorf("ParsePage returned wrong page: got %+v want %+v", request, c.expected)
+			}
+		})
+	}
+
+	if offset := (responder.PageRequest{Number: 3, PerPage: 20}).Offset(); offset != 40 {
+		t.Errorf("PageRequest returned wrong offset: got %d want 40", offset)
+	}
+
+	invalid := []struct {
+		query string
+		param string
+	}{
+		{"?page=0", "page"},
+		{"?page=two", "page"},
+		{"?per_page=-1", "per_page"},
+		{"?cursor=abc", "cursor"},
+	}
+
+	for _, c := range invalid {
+		t.Run("it rejects "+c.query, func(t *testing.T) {
+			_, err := responder.ParsePage(buildPageRequest(t, "http://localhost/customers"+c.query), limits)
+
+			var paramErr *responder.ParamError
+			if !errors.As(err, &paramErr) || paramErr.Param != c.param {
+				t.Errorf("ParsePage returned wrong error: got %v want %s", err, c.param)
+			}
+		})
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	limits := responder.DefaultPageLimits()
+
+	t.Run("it reads the cursor", func(t *testing.T) {
+		request, err := responder.ParseCursor(buildPageRequest(t, "http://localhost/customers?cursor=abc&per_page=10"), limits)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if request != (responder.PageRequest{Cursor: "abc", PerPage: 10}) {
+			t.Errorf("ParseCursor returned wrong page: got %+v", request)
+		}
+	})
+
+	t.Run("it rejects the page number", func(t *testing.T) {
+		_, err := responder.ParseCursor(buildPageRequest(t, "http://localhost/customers?page=2"), limits)
+
+		rr := httptest.NewRecorder()
+		responder.New(rr).Error(err)
+
+		assertBadRequest(t, rr)
+	})
+}
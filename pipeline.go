@@ -0,0 +1,97 @@
+package responder
+
+import (
+	"bytes"
+	"net/http"
+)
+
+// pipeline stages the status and body of a response and commits them to
+// the writer exactly once. The body is buffered up to the limit, past it
+// the response is committed and the rest of it is streamed. The headers
+// are the ones of the writer, so the ones set on it later are kept
+type pipeline struct {
+	w         http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	limit     int
+	committed bool
+}
+
+func newPipeline(w http.ResponseWriter, limit int) *pipeline {
+	return &pipeline{w: w, limit: limit}
+}
+
+// Header the headers of the writer, they can be changed until the response
+// is committed no matter if the status was already written
+func (p *pipeline) Header() http.Header {
+	return p.w.Header()
+}
+
+// WriteHeader stages the status, only the first one is kept
+func (p *pipeline) WriteHeader(statusCode int) {
+	if p.committed || p.status != 0 {
+		return
+	}
+
+	p.status = statusCode
+}
+
+func (p *pipeline) Write(b []byte) (int, error) {
+	if p.status == 0 {
+		p.status = http.StatusOK
+	}
+
+	if p.committed {
+		return p.w.Write(b)
+	}
+
+	if p.limit >= 0 && p.body.Len()+len(b) > p.limit {
+		p.commit()
+		return p.w.Write(b)
+	}
+
+	return p.body.Write(b)
+}
+
+// Flush commits the response and flushes it when the writer supports it
+func (p *pipeline) Flush() {
+	p.commit()
+
+	if flusher, ok := p.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// canFlush whether the writer can send the response to the client
+// before it is complete
+func (p *pipeline) canFlush() bool {
+	_, ok := p.w.(http.Flusher)
+	return ok
+}
+
+// commit writes the staged response, it does nothing when there's
+// nothing staged or it was already committed
+func (p *pipeline) commit() {
+	if p.committed || p.status == 0 {
+		return
+	}
+
+	p.committed = true
+	p.w.WriteHeader(p.status)
+
+	if p.body.Len() > 0 {
+		p.body.WriteTo(p.w)
+	}
+}
+
+// reset discards the staged status and body so another response can be
+// written, it reports false when the response was already committed
+func (p *pipeline) reset() bool {
+	if p.committed {
+		return false
+	}
+
+	p.status = 0
+	p.body.Reset()
+	return true
+}
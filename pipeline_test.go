@@ -0,0 +1,210 @@
+package responder_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestPipeline(t *testing.T) {
+	cases := []struct {
+		handler http.HandlerFunc
+		name    string
+	}{
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).With("success", "created!").OK(map[string]interface{}{"customer": "Henry"})
+			},
+			name: "OK",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).Created(r, 1)
+			},
+			name: "Created",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).Error(new(notFound))
+			},
+			name: "Error",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w, responder.WithErrorFormat(responder.FormatProblem)).InternalServerError(errors.New("some error"))
+			},
+			name: "InternalServerError",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).OK(func() {})
+			},
+			name: "OK failing to encode",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w, responder.WithBufferLimit(16)).OK(benchmarkPayload())
+			},
+			name: "OK streamed",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).Plain([]byte("total"), "report.txt")
+			},
+			name: "Plain",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).PDF([]byte("%PDF-1.7"))
+			},
+			name: "PDF",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).Excel([]byte("PK"))
+			},
+			name: "Excel",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).File(struct{ *strings.Reader }{strings.NewReader("total")}, "report.txt", responder.Attachment, "")
+			},
+			name: "File",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				r.Header.Set("Range", "bytes=0-1")
+				responder.New(w).Content(r, "report.txt", time.Now(), strings.NewReader("total"))
+			},
+			name: "Content",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				responder.New(w).Stream(func(ctx context.Context, yield func(item interface{}) bool) error {
+					yield(1)
+					yield(2)
+					return nil
+				})
+			},
+			name: "Stream",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				stream := responder.New(w).Events()
+				stream.Send(responder.Event{Data: "Henry"})
+				stream.Close()
+			},
+			name: "Events",
+		},
+		{
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				respond := responder.New(w)
+				respond.OK(1)
+				respond.NotFound(nil)
+			},
+			name: "responding twice",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run("it commits once when respond "+item.name, func(t *testing.T) {
+			rr := newStrictRecorder(t)
+			item.handler(rr, buildRequest(t))
+
+			if !rr.wroteHeader {
+				t.Errorf("expected the response to be committed")
+			}
+		})
+	}
+
+	t.Run("it keeps the first response", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		respond := responder.New(rr)
+		respond.OK(1)
+		respond.NotFound(nil)
+
+		assertOK(t, rr.ResponseRecorder)
+		if rr.Body.String() != "1" {
+			t.Errorf("handler returned wrong body: got %v want %v", rr.Body.String(), "1")
+		}
+	})
+
+	t.Run("it keeps the headers set before the responder", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		rr.Header().Set("X-Request-Id", "42")
+		responder.New(rr).OK(1)
+
+		if rr.Header().Get("X-Request-Id") != "42" {
+			t.Errorf("handler returned wrong request id: got %v want %v", rr.Header().Get("X-Request-Id"), "42")
+		}
+	})
+
+	t.Run("it keeps the headers set after the responder", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		respond := responder.New(rr)
+		rr.Header().Set("X-Request-Id", "42")
+		respond.OK(1)
+
+		if rr.Header().Get("X-Request-Id") != "42" {
+			t.Errorf("handler returned wrong request id: got %v want %v", rr.Header().Get("X-Request-Id"), "42")
+		}
+
+		assertContentType(t, rr, "application/json")
+	})
+}
+
+// strictRecorder fails the test on the mistakes httptest.ResponseRecorder
+// forgives: changing the headers after writing them and writing the
+// status twice, a real server ignores both
+type strictRecorder struct {
+	*httptest.ResponseRecorder
+	t           *testing.T
+	wroteHeader bool
+	written     http.Header
+}
+
+func newStrictRecorder(t *testing.T) *strictRecorder {
+	rr := &strictRecorder{ResponseRecorder: httptest.NewRecorder(), t: t}
+	t.Cleanup(rr.check)
+	return rr
+}
+
+func (rr *strictRecorder) WriteHeader(statusCode int) {
+	if rr.wroteHeader {
+		rr.t.Errorf("superfluous WriteHeader call with %d", statusCode)
+		return
+	}
+
+	rr.wroteHeader = true
+	rr.written = rr.ResponseRecorder.Header().Clone()
+	rr.ResponseRecorder.WriteHeader(statusCode)
+}
+
+func (rr *strictRecorder) Write(p []byte) (int, error) {
+	if !rr.wroteHeader {
+		rr.WriteHeader(http.StatusOK)
+	}
+
+	return rr.ResponseRecorder.Write(p)
+}
+
+func (rr *strictRecorder) Flush() {
+	if !rr.wroteHeader {
+		rr.WriteHeader(http.StatusOK)
+	}
+
+	rr.ResponseRecorder.Flush()
+}
+
+func (rr *strictRecorder) check() {
+	if rr.wroteHeader && !reflect.DeepEqual(rr.written, rr.ResponseRecorder.Header()) {
+		rr.t.Errorf("headers changed after WriteHeader: got %v want %v", rr.ResponseRecorder.Header(), rr.written)
+	}
+}
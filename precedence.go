@@ -0,0 +1,88 @@
+package responder
+
+import "errors"
+
+// Precedence picks the ErrorFormatter rendering an error wrapping,
+// or joining, several of them
+type Precedence int
+
+const (
+	// FirstFormatter the first one found walking the chain depth-first, like errors.As
+	FirstFormatter Precedence = iota
+	// LastFormatter the last one found walking the chain depth-first,
+	// usually the closest to the root cause
+	LastFormatter
+	// HighestStatus the one with the highest status, so the server errors
+	// win over the client errors
+	HighestStatus
+	// LowestStatus the one with the lowest status
+	LowestStatus
+)
+
+// WithPrecedence sets how the ErrorFormatter is picked when the error
+// wraps several of them
+func WithPrecedence(precedence Precedence) Option {
+	return func(config *Config) {
+		config.Precedence = precedence
+	}
+}
+
+// resolveFormatter finds the ErrorFormatter the error wraps following the
+// precedence, errors.Join multi-errors are walked as well
+func resolveFormatter(err error, precedence Precedence) (ErrorFormatter, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	if precedence == FirstFormatter {
+		var value ErrorFormatter
+		ok := errors.As(err, &value)
+		return value, ok
+	}
+
+	var found ErrorFormatter
+	for _, item := range unwrapChain(err) {
+		value, ok := asFormatter(item)
+		if !ok {
+			continue
+		}
+
+		if found == nil || precedence.prefers(value, found) {
+			found = value
+		}
+	}
+
+	return found, found != nil
+}
+
+// asFormatter the error itself as an ErrorFormatter, without unwrapping it
+func asFormatter(err error) (ErrorFormatter, bool) {
+	if value, ok := err.(ErrorFormatter); ok {
+		return value, true
+	}
+
+	var value ErrorFormatter
+	if custom, ok := err.(interface{ As(interface{}) bool }); ok && custom.As(&value) {
+		return value, true
+	}
+
+	return nil, false
+}
+
+// prefers reports whether the candidate wins over the one found before it
+func (precedence Precedence) prefers(candidate, found ErrorFormatter) bool {
+	switch precedence {
+	case LastFormatter:
+		return true
+	case HighestStatus:
+		return errorStatus(candidate) > errorStatus(found)
+	case LowestStatus:
+		return errorStatus(candidate) < errorStatus(found)
+	}
+
+	return false
+}
+
+func (response *HttpResponse) resolve(err error) (ErrorFormatter, bool) {
+	return resolveFormatter(err, response.config.Precedence)
+}
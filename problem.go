@@ -0,0 +1,185 @@
+package responder
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// ErrorFormat selects how the error responses are rendered
+type ErrorFormat int
+
+const (
+	// FormatDefault renders the code, message, description and info_url members
+	FormatDefault ErrorFormat = iota
+	// FormatProblem renders RFC 9457 problem details as application/problem+json
+	FormatProblem
+)
+
+// WithErrorFormat selects how the error responses are rendered
+func WithErrorFormat(format ErrorFormat) Option {
+	return func(config *Config) {
+		config.ErrorFormat = format
+	}
+}
+
+// ProblemExtender is implemented by the errors contributing
+// extension members to their problem details
+type ProblemExtender interface {
+	// Extensions returns the members added next to the standard
+	// ones, they never override them
+	Extensions() map[string]interface{}
+}
+
+// Problem RFC 9457 problem details object
+type Problem struct {
+	// A URI reference that identifies the problem type
+	Type string
+	// A short, human-readable summary of the problem type
+	Title string
+	// The HTTP status code generated by the origin server
+	Status int
+	// A human-readable explanation specific to this occurrence of the problem
+	Detail string
+	// A URI reference that identifies the specific occurrence of the problem
+	Instance string
+	// Additional members describing the problem
+	Extensions map[string]interface{}
+}
+
+// NewProblem builds the problem details for the given status and error,
+// the methods of the ErrorFormatter it wraps are mapped to their problem members
+func NewProblem(statusCode int, err error, r *http.Request) *Problem {
+	problem := &Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(statusCode),
+		Status:     statusCode,
+		Extensions: make(map[string]interface{}),
+	}
+
+	if r != nil && r.URL != nil {
+		problem.Instance = r.URL.Path
+	}
+
+	if err == nil {
+		return problem
+	}
+
+	var value ErrorFormatter
+	if !errors.As(err, &value) {
+		problem.Detail = err.Error()
+		return problem
+	}
+
+	problem.Title = value.Error()
+	problem.Extensions["code"] = value.Code()
+
+	if value.InfoURL() != nil {
+		problem.Type = *value.InfoURL()
+	}
+
+	if value.Description() != nil {
+		problem.Detail = *value.Description()
+	}
+
+	var extender ProblemExtender
+	if errors.As(err, &extender) {
+		for name, member := range extender.Extensions() {
+			problem.Extensions[name] = member
+		}
+	}
+
+	return problem
+}
+
+// MarshalJSON renders the extension members next to the standard ones
+func (problem Problem) MarshalJSON() ([]byte, error) {
+	data := make(map[string]interface{}, len(problem.Extensions)+5)
+	for name, member := range problem.Extensions {
+		data[name] = member
+	}
+
+	data["type"] = problem.Type
+	data["title"] = problem.Title
+	data["status"] = problem.Status
+
+	if problem.Detail != "" {
+		data["detail"] = problem.Detail
+	}
+
+	if problem.Instance != "" {
+		data["instance"] = problem.Instance
+	}
+
+	return json.Marshal(data)
+}
+
+// MarshalXML renders the problem on the RFC 9457 XML format
+func (problem Problem) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{
+		Name: xml.Name{Local: "problem"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "urn:ietf:rfc:7807"}},
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	members := [][2]string{
+		{"type", problem.Type},
+		{"title", problem.Title},
+		{"status", strconv.Itoa(problem.Status)},
+		{"detail", problem.Detail},
+		{"instance", problem.Instance},
+	}
+
+	for _, member := range members {
+		if member[1] == "" {
+			continue
+		}
+
+		if err := e.EncodeElement(member[1], xml.StartElement{Name: xml.Name{Local: member[0]}}); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(problem.Extensions))
+	for name := range problem.Extensions {
+		if !isProblemMember(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := e.EncodeElement(problem.Extensions[name], xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+func isProblemMember(name string) bool {
+	switch name {
+	case "type", "title", "status", "detail", "instance":
+		return true
+	}
+
+	return false
+}
+
+// problemMediaType the problem details variant of the negotiated media type
+func problemMediaType(mediaType string) string {
+	switch mediaType {
+	case "application/json":
+		return "application/problem+json"
+	case "application/xml":
+		return "application/problem+xml"
+	}
+
+	return mediaType
+}
@@ -0,0 +1,108 @@
+package responder_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestProblemDetails(t *testing.T) {
+
+	t.Run("it maps the ErrorFormatter into problem members", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := buildRequest(t)
+		respond := responder.New(rr, responder.WithErrorFormat(responder.FormatProblem), responder.WithRequest(req))
+		respond.Error(new(notFound))
+
+		assertNotFound(t, rr)
+		assertIsProblem(t, rr)
+
+		responseMap := transform(t, rr)
+		expected := map[string]interface{}{
+			"type":     "resource not found URL",
+			"title":    "resource not found",
+			"status":   float64(http.StatusNotFound),
+			"detail":   "resource not found description",
+			"instance": "/ok",
+			"code":     float64(5),
+		}
+
+		for key, value := range expected {
+			if responseMap[key] != value {
+				t.Errorf("handler returned wrong %s: got %v want %v", key, responseMap[key], value)
+			}
+		}
+	})
+
+	t.Run("it renders the extension members", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithErrorFormat(responder.FormatProblem))
+		respond.Conflict(new(outOfCredit))
+
+		assertConflict(t, rr)
+
+		responseMap := transform(t, rr)
+		if responseMap["balance"].(float64) != 30 {
+			t.Errorf("handler returned wrong balance: got %v want %v", responseMap["balance"], 30)
+		}
+
+		if responseMap["type"] != "about:blank" {
+			t.Errorf("handler returned wrong type: got %v want %v", responseMap["type"], "about:blank")
+		}
+
+		if responseMap["title"] != "out of credit" {
+			t.Errorf("extension members must not override the standard ones: got %v", responseMap["title"])
+		}
+	})
+
+	t.Run("it renders plain errors", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.New(rr, responder.WithErrorFormat(responder.FormatProblem))
+		respond.BadRequest(errors.New("invalid payload"))
+
+		assertBadRequest(t, rr)
+		assertIsProblem(t, rr)
+
+		responseMap := transform(t, rr)
+		if responseMap["title"] != http.StatusText(http.StatusBadRequest) || responseMap["detail"] != "invalid payload" {
+			t.Errorf("handler returned wrong problem: got %v", responseMap)
+		}
+	})
+
+	t.Run("it can be selected globally", func(t *testing.T) {
+		responder.Configure(responder.WithErrorFormat(responder.FormatProblem))
+		defer responder.Configure(responder.WithErrorFormat(responder.FormatDefault))
+
+		rr := httptest.NewRecorder()
+		responder.New(rr).Forbidden(nil)
+
+		assertStatusCode(t, http.StatusForbidden, rr.Code)
+		assertIsProblem(t, rr)
+	})
+}
+
+func assertIsProblem(t *testing.T, w http.ResponseWriter) {
+	if w.Header().Get("Content-Type") != "application/problem+json" {
+		t.Errorf("handler returned wrong content type: got %v want %v",
+			w.Header().Get("Content-Type"), "application/problem+json")
+	}
+}
+
+type outOfCredit struct {
+	responder.ErrorDescriptor
+}
+
+func (outOfCredit) Code() int {
+	return 7
+}
+
+func (outOfCredit) Error() string {
+	return "out of credit"
+}
+
+func (outOfCredit) Extensions() map[string]interface{} {
+	return map[string]interface{}{"balance": 30, "title": "overridden"}
+}
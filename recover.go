@@ -0,0 +1,132 @@
+package responder
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError describes the value a handler panicked with
+type PanicError struct {
+	// Value the value given to panic
+	Value interface{}
+	// Stack the stack trace of the goroutine when it panicked
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", err.Value)
+}
+
+// Unwrap returns the value given to panic when it is an error
+func (err *PanicError) Unwrap() error {
+	wrapped, _ := err.Value.(error)
+	return wrapped
+}
+
+// Recover renders the panics of the handlers as unexpected server errors
+// through a Respond configured with the options. The panics with
+// http.ErrAbortHandler are left to net/http, and so are the ones happening
+// once the headers were sent: the response is aborted instead of written
+func Recover(opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tracked := newRecoveryWriter(w)
+			defer recoverWith(tracked, func() *Respond {
+				return NewWithRequest(tracked, r, opts...)
+			})
+
+			next.ServeHTTP(tracked, r)
+		})
+	}
+}
+
+// recoverWith renders the panic of the handler, if any, with the Respond
+// given by respond. It must be deferred
+func recoverWith(w *recoveryWriter, respond func() *Respond) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	if recovered == http.ErrAbortHandler {
+		panic(recovered)
+	}
+
+	err := &PanicError{Value: recovered, Stack: debug.Stack()}
+	res := respond()
+
+	if w.wroteHeader || !res.response.writer.reset() {
+		// the client must not take the partial response as a complete one
+		res.response.report(w.status, err, res.response.hide(w.status, err))
+		panic(http.ErrAbortHandler)
+	}
+
+	// the representation headers of the handler, like Content-Disposition,
+	// don't describe the error; the cross-cutting ones, like CORS, are kept
+	w.dropRepresentation()
+	res.InternalServerError(err)
+}
+
+// recoveryWriter tracks whether the headers were sent to the client
+type recoveryWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	status      int
+}
+
+func newRecoveryWriter(w http.ResponseWriter) *recoveryWriter {
+	return &recoveryWriter{ResponseWriter: w}
+}
+
+// representationHeaders the headers describing the body the handler was writing
+var representationHeaders = []string{
+	"Content-Type",
+	"Content-Disposition",
+	"Content-Length",
+	"Content-Encoding",
+	"Content-Range",
+	"ETag",
+	"Last-Modified",
+}
+
+// dropRepresentation drops the headers describing the body of the handler
+func (w *recoveryWriter) dropRepresentation() {
+	header := w.ResponseWriter.Header()
+	for _, name := range representationHeaders {
+		header.Del(name)
+	}
+}
+
+func (w *recoveryWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader && statusCode >= http.StatusOK {
+		w.wroteHeader = true
+		w.status = statusCode
+	}
+
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// sent records the implicit http.StatusOK of the writes and flushes
+func (w *recoveryWriter) sent() {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = http.StatusOK
+	}
+}
+
+func (w *recoveryWriter) Write(b []byte) (int, error) {
+	w.sent()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends the headers and flushes the response when the writer supports it
+func (w *recoveryWriter) Flush() {
+	w.sent()
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the writer being tracked, for http.ResponseController
+func (w *recoveryWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,175 @@
+package responder_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+	"github.com/martin3zra/responder/respondertest"
+)
+
+func TestRecover(t *testing.T) {
+
+	t.Run("it renders the panic as a server error", func(t *testing.T) {
+		reporter := respondertest.NewReporter()
+		var logged responder.ErrorLog
+		recoverer := responder.Recover(
+			responder.WithReporter(reporter),
+			responder.WithErrorLogger(func(entry responder.ErrorLog) { logged = entry }),
+		)
+
+		handler := recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("out of range")
+		}))
+
+		rr := newStrictRecorder(t)
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertInternalError(t, rr.ResponseRecorder)
+		assertIsJSON(t, rr)
+		if _, ok := transform(t, rr.ResponseRecorder)["error_id"]; !ok {
+			t.Errorf("handler returned a body without error_id: got %s", rr.Body.String())
+		}
+
+		report, ok := reporter.Last()
+		if !ok {
+			t.Fatal("reporter didn't receive the error")
+		}
+
+		var panicErr *responder.PanicError
+		if !errors.As(report.Err, &panicErr) || panicErr.Value != "out of range" {
+			t.Errorf("reporter received wrong error: got %v", report.Err)
+		}
+
+		if !bytes.Contains(report.Stack, []byte("recover_test.go")) {
+			t.Errorf("reporter received a stack without the panic: got %s", report.Stack)
+		}
+
+		if !bytes.Equal(logged.Stack, report.Stack) {
+			t.Errorf("logger received wrong stack: got %s", logged.Stack)
+		}
+	})
+
+	t.Run("it drops the representation headers set by the handler", func(t *testing.T) {
+		cors := func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+				next.ServeHTTP(w, r)
+			})
+		}
+
+		recoverer := responder.Recover(responder.WithErrorLogger(func(responder.ErrorLog) {}))
+		handler := recoverer(cors(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Disposition", `attachment; filename="report.csv"`)
+			w.Header().Set("Content-Type", "text/csv")
+			w.Header().Set("ETag", `"report"`)
+			panic("out of range")
+		})))
+
+		rr := newStrictRecorder(t)
+		rr.Header().Set("X-Request-Id", "42")
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertInternalError(t, rr.ResponseRecorder)
+		assertIsJSON(t, rr)
+		if disposition := rr.Header().Get("Content-Disposition"); disposition != "" {
+			t.Errorf("handler returned unexpected disposition: got %q", disposition)
+		}
+
+		if etag := rr.Header().Get("ETag"); etag != "" {
+			t.Errorf("handler returned unexpected etag: got %q", etag)
+		}
+
+		if origin := rr.Header().Get("Access-Control-Allow-Origin"); origin != "*" {
+			t.Errorf("handler returned wrong allowed origin: got %q want %q", origin, "*")
+		}
+
+		if id := rr.Header().Get("X-Request-Id"); id != "42" {
+			t.Errorf("handler returned wrong request id: got %q want %q", id, "42")
+		}
+	})
+
+	t.Run("it leaves http.ErrAbortHandler to net/http", func(t *testing.T) {
+		handler := responder.Recover()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		rr := httptest.NewRecorder()
+		recovered := servePanicking(handler, rr, buildRequest(t))
+
+		if recovered != http.ErrAbortHandler {
+			t.Errorf("handler panicked with wrong value: got %v want %v", recovered, http.ErrAbortHandler)
+		}
+
+		if rr.Body.Len() > 0 {
+			t.Errorf("handler wrote unexpected body: got %s", rr.Body.String())
+		}
+	})
+
+	t.Run("it aborts the response once the headers were sent", func(t *testing.T) {
+		reporter := respondertest.NewReporter()
+		recoverer := responder.Recover(
+			responder.WithReporter(reporter),
+			responder.WithErrorLogger(func(responder.ErrorLog) {}),
+		)
+
+		handler := recoverer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"partial":`))
+			panic("out of range")
+		}))
+
+		rr := newStrictRecorder(t)
+		recovered := servePanicking(handler, rr, buildRequest(t))
+
+		if recovered != http.ErrAbortHandler {
+			t.Errorf("handler panicked with wrong value: got %v want %v", recovered, http.ErrAbortHandler)
+		}
+
+		if rr.Body.String() != `{"partial":` {
+			t.Errorf("handler wrote unexpected body: got %s", rr.Body.String())
+		}
+
+		report, ok := reporter.Last()
+		if !ok || report.Status != http.StatusAccepted || report.Stack == nil {
+			t.Errorf("reporter received wrong report: got %+v", report)
+		}
+	})
+
+	t.Run("it discards the staged response", func(t *testing.T) {
+		handler := responder.Middleware(responder.WithErrorLogger(func(responder.ErrorLog) {}))(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				responder.From(r).OK([]interface{}{customer{Name: "Henry"}, panicking{}})
+			}),
+		)
+
+		rr := newStrictRecorder(t)
+		handler.ServeHTTP(rr, buildRequest(t))
+
+		assertInternalError(t, rr.ResponseRecorder)
+		assertIsJSON(t, rr)
+		if bytes.Contains(rr.Body.Bytes(), []byte("Henry")) {
+			t.Errorf("handler wrote the staged body: got %s", rr.Body.String())
+		}
+	})
+}
+
+// servePanicking serves the request returning the value the handler panicked with
+func servePanicking(handler http.Handler, w http.ResponseWriter, r *http.Request) (recovered interface{}) {
+	defer func() {
+		recovered = recover()
+	}()
+
+	handler.ServeHTTP(w, r)
+	return nil
+}
+
+// panicking panics while it is encoded
+type panicking struct{}
+
+func (panicking) MarshalJSON() ([]byte, error) {
+	panic("out of range")
+}
@@ -0,0 +1,146 @@
+package responder
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// Report describes an error response sent to the client
+type Report struct {
+	// Request the request being responded, nil when it isn't known
+	Request *http.Request
+	// Status the HTTP status code sent to the client
+	Status int
+	// Err the error given to the responder, nil when there wasn't any
+	Err error
+	// Chain the errors found unwrapping Err, starting with Err itself
+	Chain []error
+	// Formatted reports whether the error was rendered by an ErrorFormatter
+	Formatted bool
+	// Code the code of the ErrorFormatter, zero when it wasn't formatted
+	Code int
+	// ErrorID the ID sent to the client when the error was hidden
+	ErrorID string
+	// Stack the stack trace of the panic the error comes from, nil otherwise
+	Stack []byte
+}
+
+// Reporter observes every error response, like the ones sent by
+// Error and InternalServerError
+type Reporter interface {
+	Report(report Report)
+}
+
+// ReporterFunc allow you use an ordinary function as a Reporter
+type ReporterFunc func(report Report)
+
+// Report calls f(report)
+func (f ReporterFunc) Report(report Report) {
+	f(report)
+}
+
+// WithReporter sets the reporter observing the error responses
+func WithReporter(reporter Reporter) Option {
+	return func(config *Config) {
+		config.Reporter = reporter
+	}
+}
+
+// SlogReporter logs the error responses, the server errors at the
+// error level and the client errors at the warn level
+type SlogReporter struct {
+	Logger *slog.Logger
+}
+
+// NewSlogReporter returns a reporter logging to the given logger,
+// slog.Default is used when it is nil
+func NewSlogReporter(logger *slog.Logger) *SlogReporter {
+	return &SlogReporter{Logger: logger}
+}
+
+// Report logs the error response
+func (reporter *SlogReporter) Report(report Report) {
+	logger := reporter.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	level := slog.LevelWarn
+	if report.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+
+	ctx := context.Background()
+	attrs := []slog.Attr{slog.Int("status", report.Status)}
+
+	if report.Request != nil {
+		ctx = report.Request.Context()
+		attrs = append(attrs, slog.String("method", report.Request.Method), slog.String("path", report.Request.URL.Path))
+	}
+
+	if report.Err != nil {
+		attrs = append(attrs, slog.String("error", report.Err.Error()))
+	}
+
+	if report.Formatted {
+		attrs = append(attrs, slog.Int("code", report.Code))
+	}
+
+	if report.ErrorID != "" {
+		attrs = append(attrs, slog.String("error_id", report.ErrorID))
+	}
+
+	logger.LogAttrs(ctx, level, "error response", attrs...)
+}
+
+func (response *HttpResponse) report(statusCode int, err error, value ErrorFormatter) {
+	reporter := response.config.Reporter
+	if reporter == nil {
+		return
+	}
+
+	report := Report{
+		Request: response.config.request,
+		Status:  statusCode,
+		Err:     err,
+		Chain:   unwrapChain(err),
+	}
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		report.Stack = panicErr.Stack
+	}
+
+	switch formatter := value.(type) {
+	case nil, *plainError:
+	case *internalError:
+		report.ErrorID = formatter.id
+	default:
+		report.Formatted = true
+		report.Code = formatter.Code()
+	}
+
+	reporter.Report(report)
+}
+
+// unwrapChain walks the wrapped errors depth-first, following
+// the errors joined by errors.Join as well
+func unwrapChain(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	chain := []error{err}
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		chain = append(chain, unwrapChain(wrapped.Unwrap())...)
+	case interface{ Unwrap() []error }:
+		for _, item := range wrapped.Unwrap() {
+			chain = append(chain, unwrapChain(item)...)
+		}
+	}
+
+	return chain
+}
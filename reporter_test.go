@@ -0,0 +1,99 @@
+package responder_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/martin3zra/responder"
+	"github.com/martin3zra/responder/respondertest"
+)
+
+func TestReporter(t *testing.T) {
+
+	t.Run("it reports the formatted errors", func(t *testing.T) {
+		reporter := respondertest.NewReporter()
+		req := buildRequest(t)
+		responder.New(httptest.NewRecorder(), responder.WithReporter(reporter), responder.WithRequest(req)).Error(new(notFound))
+
+		report, ok := reporter.Last()
+		if !ok {
+			t.Fatalf("expected a report")
+		}
+
+		if report.Status != http.StatusNotFound || !report.Formatted || report.Code != 5 || report.Request != req {
+			t.Errorf("reporter received wrong report: got %+v", report)
+		}
+	})
+
+	t.Run("it reports the unexpected errors with their chain", func(t *testing.T) {
+		reporter := respondertest.NewReporter()
+		cause := errors.New("connection refused")
+		err := fmt.Errorf("loading customer: %w", cause)
+
+		responder.New(httptest.NewRecorder(), responder.WithReporter(reporter), responder.WithErrorLogger(nil)).Error(err)
+
+		report, _ := reporter.Last()
+		if report.Status != http.StatusInternalServerError || report.Formatted || report.ErrorID == "" {
+			t.Errorf("reporter received wrong report: got %+v", report)
+		}
+
+		if len(report.Chain) != 2 || report.Chain[0] != err || report.Chain[1] != cause {
+			t.Errorf("reporter received wrong chain: got %v", report.Chain)
+		}
+	})
+
+	t.Run("it reports the errors without details", func(t *testing.T) {
+		reporter := respondertest.NewReporter()
+		respond := responder.New(httptest.NewRecorder(), responder.WithReporter(reporter))
+		respond.Conflict(nil)
+
+		report, _ := reporter.Last()
+		if report.Status != http.StatusConflict || report.Err != nil {
+			t.Errorf("reporter received wrong report: got %+v", report)
+		}
+	})
+
+	t.Run("it doesn't report the success responses", func(t *testing.T) {
+		reporter := respondertest.NewReporter()
+		responder.New(httptest.NewRecorder(), responder.WithReporter(reporter)).OK(nil)
+
+		if len(reporter.Reports()) != 0 {
+			t.Errorf("expected no reports: got %v", reporter.Reports())
+		}
+	})
+}
+
+func TestSlogReporter(t *testing.T) {
+	cases := []struct {
+		err         error
+		expectLevel string
+		name        string
+	}{
+		{err: new(notFound), expectLevel: "WARN", name: "it logs the client errors as warnings"},
+		{err: errors.New("some error"), expectLevel: "ERROR", name: "it logs the server errors as errors"},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			reporter := responder.NewSlogReporter(slog.New(slog.NewJSONHandler(&buf, nil)))
+			respond := responder.New(httptest.NewRecorder(), responder.WithReporter(reporter), responder.WithRequest(buildRequest(t)), responder.WithErrorLogger(nil))
+			respond.Error(item.err)
+
+			entry := make(map[string]interface{})
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("Cannot convert to json: %v", err)
+			}
+
+			if entry["level"] != item.expectLevel || entry["error"] != item.err.Error() || entry["path"] != "/ok" {
+				t.Errorf("reporter logged wrong entry: got %v", entry)
+			}
+		})
+	}
+}
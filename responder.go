@@ -1,23 +1,45 @@
 package responder
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
-// NEW return a new instance of the Respond object
-func New(w http.ResponseWriter) *Respond {
-	response := newHttpResponse(w, make(map[string]string))
+// NEW return a new instance of the Respond object, the options are
+// applied on top of the package-level configuration
+func New(w http.ResponseWriter, opts ...Option) *Respond {
+	response := newHttpResponse(w, make(map[string]string), newConfig(opts))
 	return &Respond{w: w, response: response}
 }
 
+// NewWithRequest return a new instance of the Respond object responding
+// the request, every feature depending on the request uses it
+func NewWithRequest(w http.ResponseWriter, r *http.Request, opts ...Option) *Respond {
+	options := make([]Option, 0, len(opts)+1)
+	options = append(options, opts...)
+	return New(w, append(options, WithRequest(r))...)
+}
+
 // Respond object struct
 type Respond struct {
 	w        http.ResponseWriter
 	response *HttpResponse
 }
 
+// Request returns the request being responded, nil when it isn't known
+func (res *Respond) Request() *http.Request {
+	return res.response.config.request
+}
+
+// Context returns the context of the request being responded
+func (res *Respond) Context() context.Context {
+	return res.response.context()
+}
+
 // With allow you set flash message
 func (res *Respond) With(name, value string) *HttpResponse {
 	res.response.setAttributes(map[string]string{
@@ -26,17 +48,28 @@ func (res *Respond) With(name, value string) *HttpResponse {
 	return res.response
 }
 
+// WithMeta allow you set a meta value rendered on the envelope
+func (res *Respond) WithMeta(key string, value interface{}) *HttpResponse {
+	return res.response.WithMeta(key, value)
+}
+
 // OK respond with http.StatusOK
 func (res *Respond) OK(payload interface{}) {
 	res.response.OK(payload)
 }
 
+// Paginated respond with http.StatusOK and the page of items
+func (res *Respond) Paginated(items interface{}, page Page) {
+	res.response.Paginated(items, page)
+}
+
 // NoContent respond with http.StatusNoContent
 func (res *Respond) NoContent() {
 	res.response.NoContent()
 }
 
-// Created respond with http.StatusCreated
+// Created respond with http.StatusCreated, the request being responded
+// builds the location when r is nil
 func (res *Respond) Created(r *http.Request, resource interface{}) {
 	res.response.Created(r, resource)
 }
@@ -81,6 +114,31 @@ func (res *Respond) Error(err error) {
 	res.response.Error(err)
 }
 
+// Stream writes the items as newline delimited JSON
+func (res *Respond) Stream(items StreamFunc) {
+	res.response.Stream(items)
+}
+
+// StreamArray writes the items as a JSON array streamed to the client
+func (res *Respond) StreamArray(items StreamFunc) {
+	res.response.StreamArray(items)
+}
+
+// Events commits the response as a server-sent event stream
+func (res *Respond) Events() *EventStream {
+	return res.response.Events()
+}
+
+// Content serves the content honoring the range and conditional headers
+func (res *Respond) Content(r *http.Request, name string, modtime time.Time, content io.ReadSeeker) {
+	res.response.Content(r, name, modtime, content)
+}
+
+// File writes the content as a file
+func (res *Respond) File(content io.Reader, name string, disposition Disposition, contentType string) {
+	res.response.File(content, name, disposition, contentType)
+}
+
 // Plain stream a plain text file
 func (res *Respond) Plain(stream []byte, fileName string) {
 	res.response.Plain(stream, fileName)
@@ -3,6 +3,7 @@ package responder_test
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -100,14 +101,55 @@ func TestResponses(t *testing.T) {
 	for _, item := range cases {
 		t.Run(item.name, func(t *testing.T) {
 
-			rr := httptest.NewRecorder()
+			rr := newStrictRecorder(t)
 			item.handler(rr, buildRequest(t))
-			item.expectCode(t, rr)
+			item.expectCode(t, rr.ResponseRecorder)
 			assertIsJSON(t, rr)
 		})
 	}
 }
 
+func TestErrorStatuses(t *testing.T) {
+	cases := []struct {
+		status       int
+		expectStatus int
+	}{
+		{status: http.StatusBadRequest, expectStatus: http.StatusBadRequest},
+		{status: http.StatusUnauthorized, expectStatus: http.StatusUnauthorized},
+		{status: http.StatusForbidden, expectStatus: http.StatusForbidden},
+		{status: http.StatusNotFound, expectStatus: http.StatusNotFound},
+		{status: http.StatusMethodNotAllowed, expectStatus: http.StatusMethodNotAllowed},
+		{status: http.StatusConflict, expectStatus: http.StatusConflict},
+		{status: http.StatusGone, expectStatus: http.StatusGone},
+		{status: http.StatusPreconditionFailed, expectStatus: http.StatusPreconditionFailed},
+		{status: http.StatusUnsupportedMediaType, expectStatus: http.StatusUnsupportedMediaType},
+		{status: http.StatusUnprocessableEntity, expectStatus: http.StatusUnprocessableEntity},
+		{status: http.StatusTooManyRequests, expectStatus: http.StatusTooManyRequests},
+		{status: http.StatusInternalServerError, expectStatus: http.StatusInternalServerError},
+		{status: http.StatusBadGateway, expectStatus: http.StatusBadGateway},
+		{status: http.StatusServiceUnavailable, expectStatus: http.StatusServiceUnavailable},
+		{status: http.StatusGatewayTimeout, expectStatus: http.StatusGatewayTimeout},
+		{status: http.StatusOK, expectStatus: http.StatusInternalServerError},
+		{status: 0, expectStatus: http.StatusInternalServerError},
+		{status: 600, expectStatus: http.StatusInternalServerError},
+	}
+
+	for _, item := range cases {
+		t.Run(fmt.Sprintf("it returns http status %d when the error status is %d", item.expectStatus, item.status), func(t *testing.T) {
+			rr := newStrictRecorder(t)
+			responder.New(rr).Error(statusError{status: item.status})
+
+			assertStatusCode(t, item.expectStatus, rr.Code)
+			assertIsJSON(t, rr)
+
+			responseMap := transform(t, rr.ResponseRecorder)
+			if responseMap["code"].(float64) != 9 || responseMap["message"] != "status error" {
+				t.Errorf("handler returned wrong error: got %v", responseMap)
+			}
+		})
+	}
+}
+
 func TestBadRequestResponse_ErrorFormatter(t *testing.T) {
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
@@ -160,14 +202,14 @@ func TestResponseContentType(t *testing.T) {
 				respond := responder.New(w)
 				respond.Excel(nil)
 			},
-			expectContentType: "application/octet-stream",
-			name:              "it returns application/octet-stream when respond Excel",
+			expectContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+			name:              "it returns the spreadsheet type when respond Excel",
 		},
 	}
 
 	for _, item := range cases {
 		t.Run(item.name, func(t *testing.T) {
-			rr := httptest.NewRecorder()
+			rr := newStrictRecorder(t)
 			item.handler(rr, buildRequest(t))
 
 			if rr.Header().Get("Content-Type") != item.expectContentType {
@@ -175,11 +217,54 @@ func TestResponseContentType(t *testing.T) {
 					rr.Header().Get("Content-Type"), item.expectContentType)
 			}
 
-			assertOK(t, rr)
+			assertOK(t, rr.ResponseRecorder)
 		})
 	}
 }
 
+func TestNewWithRequest(t *testing.T) {
+
+	t.Run("it builds the location from the stored request", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		respond := responder.NewWithRequest(rr, buildRequest(t))
+		respond.Created(nil, 1)
+
+		assertCreated(t, rr)
+		if location := rr.Header().Get("Location"); location != "http://localhost/ok/1" {
+			t.Errorf("handler returned wrong location: got %q want %q", location, "http://localhost/ok/1")
+		}
+	})
+
+	t.Run("it negotiates with the stored request", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := buildRequest(t)
+		req.Header.Set("Accept", "application/xml")
+
+		respond := responder.NewWithRequest(rr, req, withXML)
+		respond.OK(customer{Name: "Henry"})
+
+		assertOK(t, rr)
+		assertContentType(t, rr, "application/xml")
+	})
+
+	t.Run("it exposes the request and its context", func(t *testing.T) {
+		req := buildRequest(t)
+		respond := responder.NewWithRequest(httptest.NewRecorder(), req)
+
+		if respond.Request() != req {
+			t.Errorf("respond returned wrong request: got %v want %v", respond.Request(), req)
+		}
+
+		if respond.Context() != req.Context() {
+			t.Errorf("respond returned wrong context")
+		}
+
+		if responder.New(httptest.NewRecorder()).Context() == nil {
+			t.Errorf("respond returned a nil context")
+		}
+	})
+}
+
 func buildRequest(t *testing.T) *http.Request {
 	req, err := http.NewRequest("GET", "http://localhost/ok", nil)
 	if err != nil {
@@ -269,3 +354,20 @@ func (formatterBadRequest) Description() *string {
 	val := "some description here"
 	return &val
 }
+
+type statusError struct {
+	responder.ErrorDescriptor
+	status int
+}
+
+func (err statusError) Status() int {
+	return err.status
+}
+
+func (statusError) Code() int {
+	return 9
+}
+
+func (statusError) Error() string {
+	return "status error"
+}
@@ -0,0 +1,57 @@
+// Package respondertest provides utilities to test the code using the responder
+package respondertest
+
+import (
+	"sync"
+
+	"github.com/martin3zra/responder"
+)
+
+// Reporter a responder.Reporter recording the reports it receives
+type Reporter struct {
+	mu      sync.Mutex
+	reports []responder.Report
+}
+
+// NewReporter returns an empty Reporter
+func NewReporter() *Reporter {
+	return new(Reporter)
+}
+
+// Report records the report
+func (reporter *Reporter) Report(report responder.Report) {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	reporter.reports = append(reporter.reports, report)
+}
+
+// Reports the reports received so far
+func (reporter *Reporter) Reports() []responder.Report {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	reports := make([]responder.Report, len(reporter.reports))
+	copy(reports, reporter.reports)
+	return reports
+}
+
+// Last the last report received, false when there isn't any
+func (reporter *Reporter) Last() (responder.Report, bool) {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	if len(reporter.reports) == 0 {
+		return responder.Report{}, false
+	}
+
+	return reporter.reports[len(reporter.reports)-1], true
+}
+
+// Reset drops the reports received so far
+func (reporter *Reporter) Reset() {
+	reporter.mu.Lock()
+	defer reporter.mu.Unlock()
+
+	reporter.reports = nil
+}
@@ -0,0 +1,232 @@
+package responder
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultHeartbeat how often an idle event stream sends a comment
+// so the proxies don't close it
+const defaultHeartbeat = 15 * time.Second
+
+// errInvalidEventField is returned when the id or the name of an event
+// would break the framing of the stream
+var errInvalidEventField = errors.New("responder: event id and name can not contain line breaks")
+
+// WithHeartbeat sets how often the event streams send a heartbeat
+// comment, zero disables them
+func WithHeartbeat(interval time.Duration) Option {
+	return func(config *Config) {
+		config.Heartbeat = interval
+	}
+}
+
+// Event a server-sent event
+type Event struct {
+	// ID sets the last event ID of the client
+	ID string
+	// Event the name of the event, the client takes it as "message" when empty
+	Event string
+	// Retry asks the client to wait the given time before reconnecting
+	Retry time.Duration
+	// Data strings and byte slices are sent as they are, any other value
+	// is encoded with the JSON encoder used by OK
+	Data interface{}
+}
+
+// EventStream writes server-sent events (text/event-stream) to the client
+type EventStream struct {
+	body    *streamWriter
+	encoder Encoder
+	ctx     context.Context
+	lastID  string
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// Events commits the response as an event stream, the stream is closed
+// when the request context is canceled or Close is called. Close must be
+// called before the handler returns, usually deferred, otherwise the
+// heartbeat may write to the response after it is finished. The heartbeat
+// needs the request: without it no goroutine is started
+func (response *HttpResponse) Events() *EventStream {
+	header := response.writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	response.writer.WriteHeader(http.StatusOK)
+
+	stream := &EventStream{
+		body:    newStreamWriter(response.writer, 0),
+		encoder: response.encoderFor("application/json"),
+		ctx:     response.context(),
+		done:    make(chan struct{}),
+	}
+
+	if response.config.request != nil {
+		stream.lastID = response.config.request.Header.Get("Last-Event-ID")
+	}
+
+	// the client learns the stream is open without waiting for the first event
+	stream.body.forceFlush()
+	stream.heartbeat(response.config.Heartbeat)
+
+	return stream
+}
+
+// LastEventID the ID of the last event the client received before
+// reconnecting, empty on the first connection
+func (stream *EventStream) LastEventID() string {
+	return stream.lastID
+}
+
+// Done is closed when the client goes away or the stream is closed
+func (stream *EventStream) Done() <-chan struct{} {
+	return stream.done
+}
+
+// Send writes the event to the client
+func (stream *EventStream) Send(event Event) error {
+	if err := stream.err(); err != nil {
+		return err
+	}
+
+	if strings.ContainsAny(event.ID, "\r\n") || strings.ContainsAny(event.Event, "\r\n") {
+		return errInvalidEventField
+	}
+
+	var frame bytes.Buffer
+	if event.ID != "" {
+		frame.WriteString("id: " + event.ID + "\n")
+	}
+
+	if event.Event != "" {
+		frame.WriteString("event: " + event.Event + "\n")
+	}
+
+	if event.Retry > 0 {
+		frame.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+
+	data, err := stream.data(event.Data)
+	if err != nil {
+		return err
+	}
+
+	if data != nil {
+		for _, line := range splitLines(string(data)) {
+			frame.WriteString("data: " + line + "\n")
+		}
+	}
+
+	frame.WriteString("\n")
+
+	_, err = stream.body.Write(frame.Bytes())
+	return err
+}
+
+// Comment writes a comment the client ignores, every line is prefixed
+func (stream *EventStream) Comment(text string) error {
+	if err := stream.err(); err != nil {
+		return err
+	}
+
+	var frame bytes.Buffer
+	for _, line := range splitLines(text) {
+		frame.WriteString(": " + line + "\n")
+	}
+	frame.WriteString("\n")
+
+	_, err := stream.body.Write(frame.Bytes())
+	return err
+}
+
+// Close stops the stream, the events sent afterwards are discarded
+func (stream *EventStream) Close() {
+	stream.closeOnce.Do(func() {
+		close(stream.done)
+	})
+
+	stream.wg.Wait()
+}
+
+func (stream *EventStream) err() error {
+	select {
+	case <-stream.done:
+		if err := stream.ctx.Err(); err != nil {
+			return err
+		}
+
+		return context.Canceled
+	default:
+		return stream.ctx.Err()
+	}
+}
+
+func (stream *EventStream) data(value interface{}) ([]byte, error) {
+	switch data := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(data), nil
+	case []byte:
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	if err := stream.encoder.Encode(&buf, value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// heartbeat sends a comment on every interval and closes the stream when
+// the client goes away. Without the request context nothing would ever
+// stop it when Close isn't called, so there's no heartbeat at all
+func (stream *EventStream) heartbeat(interval time.Duration) {
+	if stream.ctx.Done() == nil {
+		return
+	}
+
+	stream.wg.Add(1)
+	go func() {
+		defer stream.wg.Done()
+
+		var tick <-chan time.Time
+		if interval > 0 {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			tick = ticker.C
+		}
+
+		for {
+			select {
+			case <-tick:
+				stream.body.Write([]byte(": heartbeat\n\n"))
+			case <-stream.ctx.Done():
+				stream.closeOnce.Do(func() {
+					close(stream.done)
+				})
+				return
+			case <-stream.done:
+				return
+			}
+		}
+	}()
+}
+
+// splitLines splits on any of the line breaks the event stream format accepts
+func splitLines(value string) []string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	value = strings.ReplaceAll(value, "\r", "\n")
+	return strings.Split(value, "\n")
+}
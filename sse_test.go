@@ -0,0 +1,170 @@
+package responder_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestEvents(t *testing.T) {
+
+	t.Run("it writes the event fields", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		stream := responder.New(rr).Events()
+
+		err := stream.Send(responder.Event{
+			ID:    "7",
+			Event: "customer",
+			Retry: 3 * time.Second,
+			Data:  customer{Name: "Henry"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := stream.Send(responder.Event{Data: "first line\nsecond line"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := stream.Comment("still here"); err != nil {
+			t.Fatal(err)
+		}
+		stream.Close()
+
+		assertOK(t, rr)
+		assertContentType(t, rr, "text/event-stream")
+		if rr.Header().Get("Cache-Control") != "no-cache" {
+			t.Errorf("handler returned wrong cache control: got %v", rr.Header().Get("Cache-Control"))
+		}
+
+		expected := "id: 7\nevent: customer\nretry: 3000\ndata: {\"name\":\"Henry\"}\n\n" +
+			"data: first line\ndata: second line\n\n" +
+			": still here\n\n"
+		if rr.Body.String() != expected {
+			t.Errorf("handler returned wrong body: got %q want %q", rr.Body.String(), expected)
+		}
+
+		if !rr.Flushed {
+			t.Errorf("expected the events to be flushed")
+		}
+	})
+
+	t.Run("it refuses line breaks on the id", func(t *testing.T) {
+		stream := responder.New(httptest.NewRecorder()).Events()
+		defer stream.Close()
+
+		if err := stream.Send(responder.Event{ID: "1\ndata: injected"}); err == nil {
+			t.Errorf("expected an error for the id with line breaks")
+		}
+	})
+
+	t.Run("it exposes the last event ID", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Last-Event-ID", "41")
+
+		stream := responder.New(httptest.NewRecorder(), responder.WithRequest(req)).Events()
+		defer stream.Close()
+
+		if stream.LastEventID() != "41" {
+			t.Errorf("stream returned wrong last event ID: got %v want %v", stream.LastEventID(), "41")
+		}
+	})
+
+	t.Run("it sends heartbeats", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := buildRequest(t).WithContext(ctx)
+
+		rr := &lockedRecorder{ResponseRecorder: httptest.NewRecorder()}
+		stream := responder.New(rr, responder.WithHeartbeat(time.Millisecond), responder.WithRequest(req)).Events()
+
+		deadline := time.Now().Add(time.Second)
+		for !strings.Contains(rr.body(), ": heartbeat\n\n") && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		stream.Close()
+
+		if !strings.Contains(rr.body(), ": heartbeat\n\n") {
+			t.Errorf("expected a heartbeat: got %q", rr.body())
+		}
+	})
+
+	t.Run("it shuts down when the client goes away", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := buildRequest(t).WithContext(ctx)
+
+		stream := responder.New(httptest.NewRecorder(), responder.WithRequest(req)).Events()
+		cancel()
+
+		select {
+		case <-stream.Done():
+		case <-time.After(time.Second):
+			t.Fatalf("expected the stream to be done")
+		}
+
+		if err := stream.Send(responder.Event{Data: "late"}); err != context.Canceled {
+			t.Errorf("expected context.Canceled: got %v", err)
+		}
+		stream.Close()
+	})
+}
+
+func TestEventsGoroutines(t *testing.T) {
+
+	t.Run("it doesn't start goroutines without the request", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		for i := 0; i < 10; i++ {
+			responder.New(httptest.NewRecorder(), responder.WithHeartbeat(time.Millisecond)).Events()
+		}
+
+		if after := runtime.NumGoroutine(); after > before {
+			t.Errorf("expected no goroutines left: got %d want %d", after, before)
+		}
+	})
+
+	t.Run("it stops the goroutines on Close", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := buildRequest(t).WithContext(ctx)
+
+		before := runtime.NumGoroutine()
+		for i := 0; i < 10; i++ {
+			stream := responder.New(httptest.NewRecorder(), responder.WithHeartbeat(time.Millisecond), responder.WithRequest(req)).Events()
+			stream.Close()
+		}
+
+		if after := runtime.NumGoroutine(); after > before {
+			t.Errorf("expected no goroutines left: got %d want %d", after, before)
+		}
+	})
+}
+
+// lockedRecorder lets the test read the body written by the heartbeats
+type lockedRecorder struct {
+	*httptest.ResponseRecorder
+	mu sync.Mutex
+}
+
+func (rr *lockedRecorder) Write(p []byte) (int, error) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return rr.ResponseRecorder.Write(p)
+}
+
+func (rr *lockedRecorder) Flush() {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	rr.ResponseRecorder.Flush()
+}
+
+func (rr *lockedRecorder) body() string {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	return rr.Body.String()
+}
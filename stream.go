@@ -0,0 +1,304 @@
+package responder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// defaultFlushInterval how often a stream is flushed to the client
+const defaultFlushInterval = 200 * time.Millisecond
+
+// StreamFunc produces the items of a stream by calling yield for each one
+// of them, it must stop as soon as yield returns false. The context is
+// canceled when the client goes away
+type StreamFunc func(ctx context.Context, yield func(item interface{}) bool) error
+
+// WithFlushInterval sets how often the streams are flushed to the client
+func WithFlushInterval(interval time.Duration) Option {
+	return func(config *Config) {
+		config.FlushInterval = interval
+	}
+}
+
+// FromChannel produces the items received from ch until it is closed,
+// ch must be a channel the items can be received from
+func FromChannel(ch interface{}) StreamFunc {
+	value := reflect.ValueOf(ch)
+	if value.Kind() != reflect.Chan || value.Type().ChanDir()&reflect.RecvDir == 0 {
+		panic("Invalid data-type: receive channel expected")
+	}
+
+	return func(ctx context.Context, yield func(item interface{}) bool) error {
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+			{Dir: reflect.SelectRecv, Chan: value},
+		}
+
+		for {
+			chosen, item, ok := reflect.Select(cases)
+			if chosen == 0 || !ok {
+				return nil
+			}
+
+			if !yield(item.Interface()) {
+				return nil
+			}
+		}
+	}
+}
+
+// streamFraming how the items of a stream are laid out on the body
+type streamFraming struct {
+	mediaType string
+	open      string
+	separator string
+	close     string
+	// compact keeps every item on a single line
+	compact bool
+}
+
+var (
+	ndjsonFraming = streamFraming{mediaType: "application/x-ndjson", close: "\n", separator: "\n", compact: true}
+	arrayFraming  = streamFraming{mediaType: "application/json", open: "[", separator: ",", close: "]"}
+)
+
+// Stream writes the items as newline delimited JSON (application/x-ndjson)
+func (response *HttpResponse) Stream(items StreamFunc) {
+	response.stream(items, ndjsonFraming)
+}
+
+// StreamArray writes the items as a JSON array streamed to the client
+func (response *HttpResponse) StreamArray(items StreamFunc) {
+	response.stream(items, arrayFraming)
+}
+
+// stream commits the response with the first item, so an error returned
+// before it is rendered through Error. Once the response is committed
+// an error aborts it
+func (response *HttpResponse) stream(items StreamFunc, framing streamFraming) {
+	ctx, cancel := context.WithCancel(response.context())
+	defer cancel()
+
+	body := newStreamWriter(response.writer, response.config.FlushInterval)
+	defer body.stop()
+
+	encoder := response.encoderFor("application/json")
+	if framing.compact {
+		encoder = compactEncoder{encoder}
+	}
+
+	count := 0
+	var failure error
+
+	err := items(ctx, func(item interface{}) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+
+		if count == 0 {
+			response.registerAttributes()
+			response.writer.Header().Set("Content-Type", framing.mediaType)
+			response.writer.WriteHeader(http.StatusOK)
+			body.start()
+			failure = body.writeString(framing.open)
+		} else {
+			failure = body.writeString(framing.separator)
+		}
+
+		if failure == nil {
+			failure = body.encode(encoder, item)
+		}
+
+		count++
+		if failure != nil {
+			cancel()
+			return false
+		}
+
+		return true
+	})
+
+	if count == 0 {
+		if err != nil {
+			response.Error(err)
+			return
+		}
+
+		response.registerAttributes()
+		empty := ""
+		if framing.open != "" {
+			empty = framing.open + framing.close
+		}
+		response.write(http.StatusOK, framing.mediaType, []byte(empty))
+		return
+	}
+
+	if response.context().Err() != nil {
+		// the client went away, there's nobody to write to
+		return
+	}
+
+	if err != nil || failure != nil {
+		panic(http.ErrAbortHandler)
+	}
+
+	body.writeString(framing.close)
+
+	// the periodic flushes commit the response as well
+	body.stop()
+	response.writer.commit()
+}
+
+func (response *HttpResponse) context() context.Context {
+	if response.config.request != nil {
+		return response.config.request.Context()
+	}
+
+	return context.Background()
+}
+
+// encoderFor the encoder registered for the media type, the zero
+// value of JSONEncoder when there isn't any
+func (response *HttpResponse) encoderFor(mediaType string) Encoder {
+	for _, item := range response.config.encoders {
+		if item.mediaType == mediaType {
+			return item.encoder
+		}
+	}
+
+	return JSONEncoder{}
+}
+
+// compactEncoder drops the insignificant whitespace of the JSON written by
+// the encoder, like the line breaks of an indenting one
+type compactEncoder struct {
+	Encoder
+}
+
+func (encoder compactEncoder) Encode(w io.Writer, v interface{}) error {
+	var buf bytes.Buffer
+	if err := encoder.Encoder.Encode(&buf, v); err != nil {
+		return err
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, buf.Bytes()); err != nil {
+		return err
+	}
+
+	_, err := compacted.WriteTo(w)
+	return err
+}
+
+// streamWriter serializes the writes of a stream with the periodic flushes
+type streamWriter struct {
+	mu       sync.Mutex
+	w        *pipeline
+	interval time.Duration
+	dirty    bool
+	done     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newStreamWriter(w *pipeline, interval time.Duration) *streamWriter {
+	return &streamWriter{w: w, interval: interval}
+}
+
+// start flushes the stream periodically until it is stopped, the
+// writes are flushed right away when there's no interval or the
+// writer can't flush
+func (s *streamWriter) start() {
+	if !s.w.canFlush() || s.interval <= 0 {
+		return
+	}
+
+	s.done = make(chan struct{})
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.mu.Lock()
+				s.flush()
+				s.mu.Unlock()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop ends the periodic flushes and flushes the rest of the stream,
+// it can be called more than once
+func (s *streamWriter) stop() {
+	if s.done != nil {
+		close(s.done)
+		s.wg.Wait()
+		s.done = nil
+	}
+
+	s.flush()
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, err := s.w.Write(p)
+	s.dirty = true
+	if s.done == nil {
+		s.flush()
+	}
+
+	return n, err
+}
+
+func (s *streamWriter) writeString(value string) error {
+	if value == "" {
+		return nil
+	}
+
+	_, err := s.Write([]byte(value))
+	return err
+}
+
+// encode writes the item at once, so the periodic flushes
+// never send half of it
+func (s *streamWriter) encode(encoder Encoder, item interface{}) error {
+	var buf bytes.Buffer
+	if err := encoder.Encode(&buf, item); err != nil {
+		return err
+	}
+
+	_, err := s.Write(buf.Bytes())
+	return err
+}
+
+func (s *streamWriter) forceFlush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dirty = true
+	s.flush()
+}
+
+// flush must be called holding the lock
+func (s *streamWriter) flush() {
+	if !s.dirty {
+		return
+	}
+
+	s.w.Flush()
+	s.dirty = false
+}
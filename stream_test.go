@@ -0,0 +1,189 @@
+package responder_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestStream(t *testing.T) {
+	rows := func(ctx context.Context, yield func(item interface{}) bool) error {
+		for _, name := range []string{"Henry", "Paul"} {
+			if !yield(customer{Name: name}) {
+				return nil
+			}
+		}
+
+		return nil
+	}
+
+	t.Run("it writes newline delimited JSON", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).Stream(rows)
+
+		assertOK(t, rr)
+		assertContentType(t, rr, "application/x-ndjson")
+		if rr.Body.String() != "{\"name\":\"Henry\"}\n{\"name\":\"Paul\"}\n" {
+			t.Errorf("handler returned wrong body: got %q", rr.Body.String())
+		}
+
+		if !rr.Flushed {
+			t.Errorf("expected the stream to be flushed")
+		}
+	})
+
+	t.Run("it keeps every item on a single line", func(t *testing.T) {
+		multiline := responder.MarshalFunc(func(v interface{}) ([]byte, error) {
+			return json.MarshalIndent(v, "", "  ")
+		})
+
+		for _, encoder := range []responder.Encoder{responder.JSONEncoder{Indent: "  "}, multiline} {
+			rr := httptest.NewRecorder()
+			responder.New(rr, responder.WithJSONEncoder(encoder)).Stream(rows)
+
+			if rr.Body.String() != "{\"name\":\"Henry\"}\n{\"name\":\"Paul\"}\n" {
+				t.Errorf("handler returned wrong body: got %q", rr.Body.String())
+			}
+		}
+	})
+
+	t.Run("it commits while flushing periodically", func(t *testing.T) {
+		many := func(ctx context.Context, yield func(item interface{}) bool) error {
+			for i := 0; i < 50; i++ {
+				if !yield(customer{Name: "Henry"}) {
+					return nil
+				}
+			}
+
+			return nil
+		}
+
+		for i := 0; i < 100; i++ {
+			rr := httptest.NewRecorder()
+			stream := responder.New(rr, responder.WithFlushInterval(time.Microsecond))
+			if i%2 == 0 {
+				stream.Stream(many)
+			} else {
+				stream.StreamArray(many)
+			}
+
+			assertOK(t, rr)
+			if count := strings.Count(rr.Body.String(), "Henry"); count != 50 {
+				t.Errorf("handler returned wrong items: got %d want 50", count)
+			}
+		}
+	})
+
+	t.Run("it doesn't flush periodically when the writer can't flush", func(t *testing.T) {
+		started := 0
+		counted := func(ctx context.Context, yield func(item interface{}) bool) error {
+			before := runtime.NumGoroutine()
+			yield(customer{Name: "Henry"})
+			started = runtime.NumGoroutine() - before
+			return nil
+		}
+
+		w := discardWriter{header: http.Header{}}
+		responder.New(w, responder.WithFlushInterval(time.Millisecond)).Stream(counted)
+
+		if started > 0 {
+			t.Errorf("expected no goroutines started: got %d", started)
+		}
+	})
+
+	t.Run("it writes a JSON array", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).StreamArray(rows)
+
+		assertOK(t, rr)
+		assertIsJSON(t, rr)
+		if rr.Body.String() != `[{"name":"Henry"},{"name":"Paul"}]` {
+			t.Errorf("handler returned wrong body: got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("it writes an empty JSON array", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).StreamArray(func(ctx context.Context, yield func(item interface{}) bool) error {
+			return nil
+		})
+
+		assertOK(t, rr)
+		if rr.Body.String() != "[]" {
+			t.Errorf("handler returned wrong body: got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("it renders the error returned before the first item", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		responder.New(rr).Stream(func(ctx context.Context, yield func(item interface{}) bool) error {
+			return new(notFound)
+		})
+
+		assertNotFound(t, rr)
+		assertIsJSON(t, rr)
+	})
+
+	t.Run("it streams the items received from a channel", func(t *testing.T) {
+		ch := make(chan customer, 2)
+		ch <- customer{Name: "Henry"}
+		ch <- customer{Name: "Paul"}
+		close(ch)
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithFlushInterval(0)).Stream(responder.FromChannel(ch))
+
+		if rr.Body.String() != "{\"name\":\"Henry\"}\n{\"name\":\"Paul\"}\n" {
+			t.Errorf("handler returned wrong body: got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("it stops when the client goes away", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := buildRequest(t).WithContext(ctx)
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithRequest(req)).StreamArray(func(ctx context.Context, yield func(item interface{}) bool) error {
+			yield(1)
+			cancel()
+			if yield(2) {
+				t.Errorf("expected yield to stop the stream")
+			}
+
+			return nil
+		})
+
+		assertOK(t, rr)
+		if rr.Body.String() != "[1" {
+			t.Errorf("handler returned wrong body: got %q", rr.Body.String())
+		}
+	})
+
+	t.Run("it stops waiting on the channel when the client goes away", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := responder.FromChannel(make(chan int))(ctx, func(item interface{}) bool {
+			t.Errorf("unexpected item: got %v", item)
+			return true
+		})
+
+		if err != nil {
+			t.Errorf("unexpected error: got %v", err)
+		}
+	})
+}
+
+func assertContentType(t *testing.T, w http.ResponseWriter, expected string) {
+	if w.Header().Get("Content-Type") != expected {
+		t.Errorf("handler returned wrong content type: got %v want %v",
+			w.Header().Get("Content-Type"), expected)
+	}
+}
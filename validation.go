@@ -0,0 +1,248 @@
+package responder
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldError a validation failure of a request field
+type FieldError struct {
+	// Field the path of the field, like items[2].qty
+	Field string
+	// Rule the code of the rule that failed, like required or gt
+	Rule string
+	// Params the parameters of the rule, like the limit of gt
+	Params map[string]interface{}
+	// Message the short localized description of the failure
+	Message string
+}
+
+// Param a parameter of the rule that failed
+type Param struct {
+	Name  string
+	Value interface{}
+}
+
+// ValidationErrors collects the validation failures of a request, they
+// are rendered with http.StatusUnprocessableEntity. Its zero value is
+// an empty collector ready to use
+type ValidationErrors struct {
+	prefix string
+	fields *[]FieldError
+}
+
+// NewValidationErrors returns an empty ValidationErrors
+func NewValidationErrors() *ValidationErrors {
+	return &ValidationErrors{fields: new([]FieldError)}
+}
+
+// FieldPath joins the segments into a field path, the integer
+// segments become indexes: FieldPath("items", 2, "qty") is items[2].qty
+func FieldPath(segments ...interface{}) string {
+	var path strings.Builder
+	for _, segment := range segments {
+		switch value := segment.(type) {
+		case int:
+			path.WriteString("[" + strconv.Itoa(value) + "]")
+		default:
+			name := fmt.Sprint(value)
+			if name == "" {
+				continue
+			}
+
+			if path.Len() > 0 && !strings.HasPrefix(name, "[") {
+				path.WriteByte('.')
+			}
+			path.WriteString(name)
+		}
+	}
+
+	return path.String()
+}
+
+// Add collects the failure of the rule for the field
+func (errs *ValidationErrors) Add(field, rule, message string, params ...Param) *ValidationErrors {
+	fieldError := FieldError{Field: field, Rule: rule, Message: message}
+	if len(params) > 0 {
+		fieldError.Params = make(map[string]interface{}, len(params))
+		for _, param := range params {
+			fieldError.Params[param.Name] = param.Value
+		}
+	}
+
+	return errs.AddError(fieldError)
+}
+
+// AddError collects the failure, its field is relative to the scope
+func (errs *ValidationErrors) AddError(fieldError FieldError) *ValidationErrors {
+	fieldError.Field = FieldPath(errs.prefix, fieldError.Field)
+	errs.init()
+	*errs.fields = append(*errs.fields, fieldError)
+	return errs
+}
+
+// Scope returns a collector adding the failures under the given path,
+// they are collected in errs as well
+func (errs *ValidationErrors) Scope(segments ...interface{}) *ValidationErrors {
+	errs.init()
+	return &ValidationErrors{
+		prefix: FieldPath(append([]interface{}{errs.prefix}, segments...)...),
+		fields: errs.fields,
+	}
+}
+
+// Fields the failures collected so far
+func (errs *ValidationErrors) Fields() []FieldError {
+	fields := make([]FieldError, len(errs.list()))
+	copy(fields, errs.list())
+	return fields
+}
+
+// HasErrors reports whether any failure was collected
+func (errs *ValidationErrors) HasErrors() bool {
+	return len(errs.list()) > 0
+}
+
+// init creates the failures shared with the scopes, the zero value has none
+func (errs *ValidationErrors) init() {
+	if errs.fields == nil {
+		errs.fields = new([]FieldError)
+	}
+}
+
+// list the failures collected so far, nil for the zero value
+func (errs *ValidationErrors) list() []FieldError {
+	if errs.fields == nil {
+		return nil
+	}
+
+	return *errs.fields
+}
+
+// Err returns the collected failures as an error, nil when there isn't any
+func (errs *ValidationErrors) Err() error {
+	if !errs.HasErrors() {
+		return nil
+	}
+
+	return errs
+}
+
+// Status HTTP Status code
+func (*ValidationErrors) Status() int {
+	return http.StatusUnprocessableEntity
+}
+
+// Code the error type
+func (*ValidationErrors) Code() int {
+	return http.StatusUnprocessableEntity
+}
+
+func (*ValidationErrors) Error() string {
+	return "validation failed"
+}
+
+// Description (optional)
+func (*ValidationErrors) Description() *string { return nil }
+
+// InfoURL (optional)
+func (*ValidationErrors) InfoURL() *string { return nil }
+
+// Extensions the failures as the RFC 9457 invalid-params member
+func (errs *ValidationErrors) Extensions() map[string]interface{} {
+	params := make([]invalidParam, 0, len(errs.list()))
+	for _, field := range errs.list() {
+		params = append(params, invalidParam{
+			Name:   field.Field,
+			Reason: field.Message,
+			Rule:   field.Rule,
+			Params: ruleParams(field.Params),
+		})
+	}
+
+	return map[string]interface{}{"invalid-params": params}
+}
+
+// fieldMessages the messages grouped by field, on the default format
+func (errs *ValidationErrors) fieldMessages() fieldMessages {
+	messages := make(fieldMessages, len(errs.list()))
+	for _, field := range errs.list() {
+		messages[field.Field] = append(messages[field.Field], field.Message)
+	}
+
+	return messages
+}
+
+// invalidParam an entry of the problem details invalid-params member
+type invalidParam struct {
+	Name   string     `json:"name" xml:"name"`
+	Reason string     `json:"reason" xml:"reason"`
+	Rule   string     `json:"rule,omitempty" xml:"rule,omitempty"`
+	Params ruleParams `json:"params,omitempty" xml:"params,omitempty"`
+}
+
+type ruleParams map[string]interface{}
+
+// MarshalXML renders every parameter as a param element
+func (params ruleParams) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(params) {
+		param := xml.StartElement{Name: xml.Name{Local: "param"}, Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: name}}}
+		if err := e.EncodeElement(params[name], param); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+type fieldMessages map[string][]string
+
+// MarshalXML renders every field as a field element holding its messages
+func (messages fieldMessages) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(messages))
+	for name := range messages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := xml.StartElement{Name: xml.Name{Local: "field"}, Attr: []xml.Attr{{Name: xml.Name{Local: "name"}, Value: name}}}
+		if err := e.EncodeElement(struct {
+			Messages []string `xml:"message"`
+		}{messages[name]}, field); err != nil {
+			return err
+		}
+	}
+
+	return e.EncodeToken(start.End())
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
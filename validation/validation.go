@@ -0,0 +1,254 @@
+// Package validation converts the field-level failures reported by tag-based
+// validators, like github.com/go-playground/validator, into the validation
+// errors of the responder. It relies on a small interface so neither package
+// depends on the other
+package validation
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/martin3zra/responder"
+)
+
+// FieldError the methods of a field-level failure the adapter relies on,
+// github.com/go-playground/validator/v10.FieldError implements it
+type FieldError interface {
+	// Tag the validation tag that failed, like required or gt
+	Tag() string
+	// Param the parameter of the tag, like the limit of gt
+	Param() string
+	// StructNamespace the path of the field by its struct names, like Order.Items[2].Qty
+	StructNamespace() string
+	// Field the name of the field
+	Field() string
+}
+
+// Messages maps the validation tags to their message, {field} and
+// {param} are replaced by the field name and the tag parameter
+type Messages map[string]string
+
+// DefaultMessages the english messages of the common tags
+var DefaultMessages = Messages{
+	"required": "is required",
+	"email":    "must be a valid email address",
+	"url":      "must be a valid URL",
+	"uuid":     "must be a valid UUID",
+	"min":      "must be at least {param}",
+	"max":      "must be at most {param}",
+	"len":      "must have a length of {param}",
+	"eq":       "must be equal to {param}",
+	"ne":       "must not be equal to {param}",
+	"gt":       "must be greater than {param}",
+	"gte":      "must be greater than or equal to {param}",
+	"lt":       "must be less than {param}",
+	"lte":      "must be less than or equal to {param}",
+	"oneof":    "must be one of {param}",
+}
+
+// fallbackMessage the message of the tags missing on the table
+const fallbackMessage = "is invalid"
+
+// Adapter converts the field-level failures using its message table
+type Adapter struct {
+	// Messages the message of every tag, DefaultMessages when nil
+	Messages Messages
+}
+
+// New returns an Adapter using the given message table
+func New(messages Messages) *Adapter {
+	return &Adapter{Messages: messages}
+}
+
+// Convert converts the failures using DefaultMessages, see Adapter.Convert
+func Convert(err error, root interface{}) (*responder.ValidationErrors, bool) {
+	return new(Adapter).Convert(err, root)
+}
+
+// Convert converts the failures held by err into validation errors. The root
+// is the validated value, its json tags name the field paths; the struct
+// names are kept when it is nil. It returns false when err doesn't hold any
+// field-level failure
+func (adapter *Adapter) Convert(err error, root interface{}) (*responder.ValidationErrors, bool) {
+	fieldErrors := collect(err)
+	if len(fieldErrors) == 0 {
+		return nil, false
+	}
+
+	messages := adapter.Messages
+	if messages == nil {
+		messages = DefaultMessages
+	}
+
+	rootType := reflect.TypeOf(root)
+	errs := responder.NewValidationErrors()
+	for _, fieldError := range fieldErrors {
+		var params []responder.Param
+		if fieldError.Param() != "" {
+			params = append(params, responder.Param{Name: fieldError.Tag(), Value: fieldError.Param()})
+		}
+
+		errs.Add(jsonPath(rootType, fieldError.StructNamespace()), fieldError.Tag(), message(messages, fieldError), params...)
+	}
+
+	return errs, true
+}
+
+// collect the failures held by err or by the errors it wraps, the
+// ones joined by errors.Join are collected together
+func collect(err error) []FieldError {
+	if err == nil {
+		return nil
+	}
+
+	if fieldErrors := collectOwn(err); len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() error }:
+		return collect(wrapped.Unwrap())
+	case interface{ Unwrap() []error }:
+		var fieldErrors []FieldError
+		for _, item := range wrapped.Unwrap() {
+			fieldErrors = append(fieldErrors, collect(item)...)
+		}
+
+		return fieldErrors
+	}
+
+	return nil
+}
+
+// collectOwn the failures err itself holds, validators usually report
+// them as a slice of their own FieldError type
+func collectOwn(err error) []FieldError {
+	if fieldError, ok := err.(FieldError); ok {
+		return []FieldError{fieldError}
+	}
+
+	value := reflect.ValueOf(err)
+	if value.Kind() != reflect.Slice {
+		return nil
+	}
+
+	fieldErrors := make([]FieldError, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		fieldError, ok := value.Index(i).Interface().(FieldError)
+		if !ok {
+			return nil
+		}
+
+		fieldErrors = append(fieldErrors, fieldError)
+	}
+
+	return fieldErrors
+}
+
+func message(messages Messages, fieldError FieldError) string {
+	template, ok := messages[fieldError.Tag()]
+	if !ok {
+		template = fallbackMessage
+	}
+
+	return strings.NewReplacer("{field}", fieldError.Field(), "{param}", fieldError.Param()).Replace(template)
+}
+
+// jsonPath translates the struct namespace into the path of the JSON
+// document, the root struct name is dropped
+func jsonPath(rootType reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+
+	current := rootType
+	path := make([]interface{}, 0, len(segments))
+	for _, segment := range segments {
+		name, indexes := splitIndexes(segment)
+
+		jsonName, embedded, next := lookupField(current, name)
+		if !embedded {
+			path = append(path, jsonName)
+		}
+
+		for _, index := range indexes {
+			path = append(path, index)
+			next = elem(next)
+		}
+
+		current = next
+	}
+
+	return responder.FieldPath(path...)
+}
+
+// splitIndexes splits Items[2][key] into Items and its [2] and [key] indexes
+func splitIndexes(segment string) (string, []string) {
+	open := strings.IndexByte(segment, '[')
+	if open < 0 {
+		return segment, nil
+	}
+
+	var indexes []string
+	rest := segment[open:]
+	for rest != "" {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			break
+		}
+
+		indexes = append(indexes, rest[:end+1])
+		rest = rest[end+1:]
+	}
+
+	return segment[:open], indexes
+}
+
+// lookupField the JSON name of the struct field, whether it is an embedded
+// struct flattened by encoding/json, and its type. The struct name is kept
+// when the field can't be found
+func lookupField(structType reflect.Type, name string) (string, bool, reflect.Type) {
+	structType = deref(structType)
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return name, false, nil
+	}
+
+	field, ok := structType.FieldByName(name)
+	if !ok {
+		return name, false, nil
+	}
+
+	tag := strings.Split(field.Tag.Get("json"), ",")[0]
+	if field.Anonymous && tag == "" && deref(field.Type).Kind() == reflect.Struct {
+		return "", true, field.Type
+	}
+
+	if tag == "" || tag == "-" {
+		return field.Name, false, field.Type
+	}
+
+	return tag, false, field.Type
+}
+
+func deref(t reflect.Type) reflect.Type {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t
+}
+
+func elem(t reflect.Type) reflect.Type {
+	t = deref(t)
+	if t == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return t.Elem()
+	}
+
+	return nil
+}
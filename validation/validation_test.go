@@ -0,0 +1,145 @@
+package validation_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/martin3zra/responder"
+	"github.com/martin3zra/responder/validation"
+)
+
+type order struct {
+	Customer `json:"customer"`
+	Email    string            `json:"email,omitempty"`
+	Items    []item            `json:"items"`
+	Notes    map[string]string `json:"notes"`
+	Internal string
+	Audit
+}
+
+type Customer struct {
+	Name string `json:"name"`
+}
+
+type Audit struct {
+	Reason string `json:"reason"`
+}
+
+type item struct {
+	Qty int `json:"qty"`
+}
+
+func TestConvert(t *testing.T) {
+	err := fieldErrors{
+		fieldError{tag: "required", namespace: "order.Email", field: "Email"},
+		fieldError{tag: "gt", param: "0", namespace: "order.Items[2].Qty", field: "Qty"},
+		fieldError{tag: "max", param: "10", namespace: "order.Notes[gift]", field: "Notes[gift]"},
+		fieldError{tag: "required", namespace: "order.Customer.Name", field: "Name"},
+		fieldError{tag: "required", namespace: "order.Audit.Reason", field: "Reason"},
+		fieldError{tag: "alpha", namespace: "order.Internal", field: "Internal"},
+	}
+
+	errs, ok := validation.Convert(err, order{})
+	if !ok {
+		t.Fatalf("expected the failures to be converted")
+	}
+
+	expected := []responder.FieldError{
+		{Field: "email", Rule: "required", Message: "is required"},
+		{Field: "items[2].qty", Rule: "gt", Message: "must be greater than 0", Params: map[string]interface{}{"gt": "0"}},
+		{Field: "notes[gift]", Rule: "max", Message: "must be at most 10", Params: map[string]interface{}{"max": "10"}},
+		{Field: "customer.name", Rule: "required", Message: "is required"},
+		{Field: "reason", Rule: "required", Message: "is required"},
+		{Field: "Internal", Rule: "alpha", Message: "is invalid"},
+	}
+
+	if !reflect.DeepEqual(errs.Fields(), expected) {
+		t.Errorf("wrong failures:\ngot  %+v\nwant %+v", errs.Fields(), expected)
+	}
+}
+
+func TestConvertWithMessages(t *testing.T) {
+	adapter := validation.New(validation.Messages{"required": "{field} es requerido"})
+
+	errs, ok := adapter.Convert(fieldErrors{fieldError{tag: "required", namespace: "order.Email", field: "Email"}}, nil)
+	if !ok {
+		t.Fatalf("expected the failures to be converted")
+	}
+
+	fields := errs.Fields()
+	if fields[0].Field != "Email" || fields[0].Message != "Email es requerido" {
+		t.Errorf("wrong failure: got %+v", fields[0])
+	}
+}
+
+func TestConvertWrappedErrors(t *testing.T) {
+	failures := fieldErrors{fieldError{tag: "required", namespace: "order.Email", field: "Email"}}
+
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{"it unwraps the failures", fmt.Errorf("validating order: %w", failures)},
+		{"it unwraps a single failure", fmt.Errorf("validating order: %w", failures[0])},
+		{"it unwraps the joined failures", errors.Join(errors.New("invalid request"), fmt.Errorf("validating order: %w", failures))},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs, ok := validation.Convert(c.err, order{})
+			if !ok {
+				t.Fatalf("expected the failures to be converted")
+			}
+
+			fields := errs.Fields()
+			if len(fields) != 1 || fields[0].Field != "email" || fields[0].Rule != "required" {
+				t.Errorf("wrong failures: got %+v", fields)
+			}
+		})
+	}
+}
+
+func TestConvertOtherErrors(t *testing.T) {
+	if _, ok := validation.Convert(errors.New("invalid validation"), nil); ok {
+		t.Errorf("expected errors without failures not to be converted")
+	}
+
+	if _, ok := validation.Convert(nil, nil); ok {
+		t.Errorf("expected nil not to be converted")
+	}
+}
+
+// fieldErrors mirrors validator.ValidationErrors, a slice of the
+// validator's own FieldError interface
+type fieldErrors []validatorFieldError
+
+func (fieldErrors) Error() string {
+	return "validation failed"
+}
+
+type validatorFieldError interface {
+	Tag() string
+	Param() string
+	StructNamespace() string
+	Field() string
+	Error() string
+}
+
+type fieldError struct {
+	tag       string
+	param     string
+	namespace string
+	field     string
+}
+
+func (err fieldError) Tag() string { return err.tag }
+
+func (err fieldError) Param() string { return err.param }
+
+func (err fieldError) StructNamespace() string { return err.namespace }
+
+func (err fieldError) Field() string { return err.field }
+
+func (err fieldError) Error() string { return err.namespace + " failed on " + err.tag }
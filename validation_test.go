@@ -0,0 +1,139 @@
+package responder_test
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/martin3zra/responder"
+)
+
+func TestFieldPath(t *testing.T) {
+	cases := []struct {
+		segments []interface{}
+		expected string
+	}{
+		{segments: []interface{}{"email"}, expected: "email"},
+		{segments: []interface{}{"items", 2, "qty"}, expected: "items[2].qty"},
+		{segments: []interface{}{"", "customer", "addresses", 0, 1}, expected: "customer.addresses[0][1]"},
+		{segments: []interface{}{"items[2]", "qty"}, expected: "items[2].qty"},
+	}
+
+	for _, item := range cases {
+		if path := responder.FieldPath(item.segments...); path != item.expected {
+			t.Errorf("wrong path for %v: got %v want %v", item.segments, path, item.expected)
+		}
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	newErrors := func() *responder.ValidationErrors {
+		errs := responder.NewValidationErrors()
+		errs.Add("email", "required", "is required")
+		errs.Add("email", "email", "must be a valid email")
+		errs.Scope("items", 2).Add("qty", "gt", "must be > 0", responder.Param{Name: "min", Value: 0})
+		return errs
+	}
+
+	t.Run("it collects the failures", func(t *testing.T) {
+		if responder.NewValidationErrors().Err() != nil {
+			t.Errorf("expected no error without failures")
+		}
+
+		errs := newErrors()
+		if errs.Err() == nil || len(errs.Fields()) != 3 {
+			t.Fatalf("expected three failures: got %v", errs.Fields())
+		}
+
+		expected := responder.FieldError{Field: "items[2].qty", Rule: "gt", Message: "must be > 0", Params: map[string]interface{}{"min": 0}}
+		if !reflect.DeepEqual(errs.Fields()[2], expected) {
+			t.Errorf("wrong scoped failure: got %+v want %+v", errs.Fields()[2], expected)
+		}
+	})
+
+	t.Run("it works with the zero value", func(t *testing.T) {
+		var errs responder.ValidationErrors
+		if errs.HasErrors() || errs.Err() != nil || len(errs.Fields()) != 0 {
+			t.Errorf("expected no failures: got %v", errs.Fields())
+		}
+
+		if _, ok := errs.Extensions()["invalid-params"]; !ok {
+			t.Errorf("expected the invalid-params member")
+		}
+
+		errs.Scope("items", 0).Add("qty", "gt", "must be > 0")
+		errs.Add("email", "required", "is required")
+
+		fields := errs.Fields()
+		if len(fields) != 2 || fields[0].Field != "items[0].qty" || fields[1].Field != "email" {
+			t.Errorf("wrong failures: got %+v", fields)
+		}
+	})
+
+	t.Run("it renders the failures by field", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		responder.New(rr).UnprocessableEntity(newErrors())
+
+		assertUnprocessableEntity(t, rr.ResponseRecorder)
+
+		responseMap := transform(t, rr.ResponseRecorder)
+		expected := map[string]interface{}{
+			"email":        []interface{}{"is required", "must be a valid email"},
+			"items[2].qty": []interface{}{"must be > 0"},
+		}
+
+		if !reflect.DeepEqual(responseMap["errors"], expected) {
+			t.Errorf("handler returned wrong errors: got %v want %v", responseMap["errors"], expected)
+		}
+	})
+
+	t.Run("it renders the problem details invalid-params", func(t *testing.T) {
+		rr := newStrictRecorder(t)
+		responder.New(rr, responder.WithErrorFormat(responder.FormatProblem)).Error(fmt.Errorf("creating order: %w", newErrors()))
+
+		assertUnprocessableEntity(t, rr.ResponseRecorder)
+		assertIsProblem(t, rr)
+
+		params, ok := transform(t, rr.ResponseRecorder)["invalid-params"].([]interface{})
+		if !ok || len(params) != 3 {
+			t.Fatalf("handler returned wrong invalid-params: got %v", rr.Body.String())
+		}
+
+		expected := map[string]interface{}{
+			"name":   "items[2].qty",
+			"reason": "must be > 0",
+			"rule":   "gt",
+			"params": map[string]interface{}{"min": float64(0)},
+		}
+
+		if !reflect.DeepEqual(params[2], expected) {
+			t.Errorf("handler returned wrong invalid param: got %v want %v", params[2], expected)
+		}
+	})
+
+	t.Run("it renders the failures as xml", func(t *testing.T) {
+		req := buildRequest(t)
+		req.Header.Set("Accept", "application/xml")
+
+		rr := httptest.NewRecorder()
+		responder.New(rr, responder.WithRequest(req), withXML).Error(newErrors())
+
+		assertUnprocessableEntity(t, rr)
+
+		var body struct {
+			Fields []struct {
+				Name     string   `xml:"name,attr"`
+				Messages []string `xml:"message"`
+			} `xml:"errors>field"`
+		}
+		if err := xml.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Cannot convert to xml: %v", err)
+		}
+
+		if len(body.Fields) != 2 || body.Fields[0].Name != "email" || len(body.Fields[0].Messages) != 2 {
+			t.Errorf("handler returned wrong errors: got %v", rr.Body.String())
+		}
+	})
+}